@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 type Options struct {
 	Addr              string
 	StrictSlash       bool
+	StrictRoutes      bool
 	ReadHeaderTimeout time.Duration
 	WriteTimeout      time.Duration
 	IdleTimeout       time.Duration
@@ -31,7 +34,9 @@ func NewOptions() Options {
 	return defaultOptions(Options{})
 }
 
-// App holds on to options and the underlying router, the middleware, and more
+// App holds on to options and the underlying router, the middleware, and more.
+// The embedded Router is the Router the App was created with, the methods of
+// the App change the Router that serves the requests
 type App struct {
 	*Router
 	opts           Options
@@ -39,17 +44,43 @@ type App struct {
 	tlsConfig      *tls.Config
 	certFile       string
 	keyFile        string
+	serving        atomic.Value
+	swapMu         sync.Mutex
 }
 
 // New creates a new App
 func New(opts Options) *App {
-	return &App{
+	a := &App{
 		Router: NewRouter(opts.StrictSlash),
 		opts:   opts,
 		autoTLSManager: autocert.Manager{
 			Prompt: autocert.AcceptTOS,
 		},
 	}
+	a.Router.SetStrictRoutes(opts.StrictRoutes)
+	a.serving.Store(a.Router)
+	return a
+}
+
+// SwapRouter replaces the Router that serves the requests of the App and
+// returns the Router that was replaced. Requests that already are served
+// by the replaced Router are finished by it. The StrictSlash and StrictRoutes
+// options of the App are applied to the new Router, and the methods of the
+// App, like GET and Use, change the new Router afterwards
+func (a *App) SwapRouter(r *Router) *Router {
+	a.swapMu.Lock()
+	defer a.swapMu.Unlock()
+
+	r.strictSlash = a.opts.StrictSlash
+	r.SetStrictRoutes(a.opts.StrictRoutes)
+
+	old := a.router()
+	a.serving.Store(r)
+	return old
+}
+
+func (a *App) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	a.router().ServeHTTP(res, req)
 }
 
 // UseAutoTLS will setup autocert.Manager and request cert from https://letsencrypt.org
@@ -87,17 +118,7 @@ func (a *App) Serve() error {
 	ctx, cancel := interruptWithCancel(a.opts.ctx)
 	defer cancel()
 
-	var s *http.Server
-
-	s = &http.Server{
-		Addr:              a.opts.Addr,
-		Handler:           a,
-		ReadHeaderTimeout: a.opts.ReadHeaderTimeout,
-		WriteTimeout:      a.opts.WriteTimeout,
-		IdleTimeout:       a.opts.IdleTimeout,
-		MaxHeaderBytes:    a.opts.MaxHeaderBytes,
-		TLSConfig:         a.tlsConfig,
-	}
+	s := a.server(ctx)
 
 	var err error
 
@@ -118,6 +139,22 @@ func (a *App) Serve() error {
 	return a.Close(s.Shutdown(ctx))
 }
 
+// server creates the http.Server of the App, the requests
+// are cancelled when the context is cancelled
+func (a *App) server(ctx gocontext.Context) *http.Server {
+	s := &http.Server{
+		Addr:              a.opts.Addr,
+		Handler:           a,
+		ReadHeaderTimeout: a.opts.ReadHeaderTimeout,
+		WriteTimeout:      a.opts.WriteTimeout,
+		IdleTimeout:       a.opts.IdleTimeout,
+		MaxHeaderBytes:    a.opts.MaxHeaderBytes,
+		TLSConfig:         a.tlsConfig,
+	}
+	withBaseContext(s, ctx)
+	return s
+}
+
 // Close the application and try to shutdown gracefully
 func (a *App) Close(err error) error {
 	a.opts.cancel()
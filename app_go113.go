@@ -0,0 +1,18 @@
+//go:build go1.13
+// +build go1.13
+
+package otto
+
+import (
+	gocontext "context"
+	"net"
+	"net/http"
+)
+
+// withBaseContext makes the requests of the server use
+// the context, so they are cancelled when it is cancelled
+func withBaseContext(s *http.Server, ctx gocontext.Context) {
+	s.BaseContext = func(net.Listener) gocontext.Context {
+		return ctx
+	}
+}
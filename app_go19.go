@@ -0,0 +1,29 @@
+//go:build !go1.13
+// +build !go1.13
+
+package otto
+
+import (
+	gocontext "context"
+	"net/http"
+)
+
+// withBaseContext cancels the requests of the server when the context is
+// cancelled, http.Server.BaseContext is not available before go1.13
+func withBaseContext(s *http.Server, ctx gocontext.Context) {
+	h := s.Handler
+	s.Handler = http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		c, cancel := gocontext.WithCancel(req.Context())
+		defer cancel()
+
+		go func() {
+			select {
+			case <-ctx.Done():
+				cancel()
+			case <-c.Done():
+			}
+		}()
+
+		h.ServeHTTP(res, req.WithContext(c))
+	})
+}
@@ -0,0 +1,170 @@
+package otto
+
+import (
+	"net/http"
+)
+
+// router returns the Router that serves the requests of the App, the methods
+// of the App change it instead of the Router the App was created with
+func (a *App) router() *Router {
+	return a.serving.Load().(*Router)
+}
+
+// SetDeprecationHook calls SetDeprecationHook of the Router that serves the requests
+func (a *App) SetDeprecationHook(fn func(ctx Context, route *Route)) {
+	a.router().SetDeprecationHook(fn)
+}
+
+// When calls When of the Router that serves the requests
+func (a *App) When(m ...Matcher) *Router {
+	return a.router().When(m...)
+}
+
+// SetMarshalFunc calls SetMarshalFunc of the Router that serves the requests
+func (a *App) SetMarshalFunc(mediaType string, fn MarshalFunc) {
+	a.router().SetMarshalFunc(mediaType, fn)
+}
+
+// Redirect calls Redirect of the Router that serves the requests
+func (a *App) Redirect(from, to string, code int) *Route {
+	return a.router().Redirect(from, to, code)
+}
+
+// SetRenderer calls SetRenderer of the Router that serves the requests
+func (a *App) SetRenderer(rn Renderer) {
+	a.router().SetRenderer(rn)
+}
+
+// Resource calls Resource of the Router that serves the requests
+func (a *App) Resource(p string, h interface{}, mf ...Middleware) *Resource {
+	return a.router().Resource(p, h, mf...)
+}
+
+// SetErrorHandlers calls SetErrorHandlers of the Router that serves the requests
+func (a *App) SetErrorHandlers(eh map[int]ErrorHandler) {
+	a.router().SetErrorHandlers(eh)
+}
+
+// GET calls GET of the Router that serves the requests
+func (a *App) GET(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return a.router().GET(p, h, mf...)
+}
+
+// POST calls POST of the Router that serves the requests
+func (a *App) POST(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return a.router().POST(p, h, mf...)
+}
+
+// PUT calls PUT of the Router that serves the requests
+func (a *App) PUT(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return a.router().PUT(p, h, mf...)
+}
+
+// DELETE calls DELETE of the Router that serves the requests
+func (a *App) DELETE(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return a.router().DELETE(p, h, mf...)
+}
+
+// OPTIONS calls OPTIONS of the Router that serves the requests
+func (a *App) OPTIONS(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return a.router().OPTIONS(p, h, mf...)
+}
+
+// HEAD calls HEAD of the Router that serves the requests
+func (a *App) HEAD(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return a.router().HEAD(p, h, mf...)
+}
+
+// PATCH calls PATCH of the Router that serves the requests
+func (a *App) PATCH(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return a.router().PATCH(p, h, mf...)
+}
+
+// SetBinder calls SetBinder of the Router that serves the requests
+func (a *App) SetBinder(b BindFunc) {
+	a.router().SetBinder(b)
+}
+
+// SetCharset calls SetCharset of the Router that serves the requests
+func (a *App) SetCharset(charset string) {
+	a.router().SetCharset(charset)
+}
+
+// SetStrictRoutes calls SetStrictRoutes of the Router that serves the requests
+func (a *App) SetStrictRoutes(strict bool) {
+	a.swapMu.Lock()
+	defer a.swapMu.Unlock()
+
+	a.opts.StrictRoutes = strict
+	a.router().SetStrictRoutes(strict)
+}
+
+// Match calls Match of the Router that serves the requests
+func (a *App) Match(methods []string, p string, h HandlerFunc, mf ...Middleware) Routes {
+	return a.router().Match(methods, p, h, mf...)
+}
+
+// Any calls Any of the Router that serves the requests
+func (a *App) Any(p string, h HandlerFunc, mf ...Middleware) Routes {
+	return a.router().Any(p, h, mf...)
+}
+
+// Group calls Group of the Router that serves the requests
+func (a *App) Group(p string, fn ...func(*Router)) *Router {
+	return a.router().Group(p, fn...)
+}
+
+// Host calls Host of the Router that serves the requests
+func (a *App) Host(tpl string, fn ...func(*Router)) *Router {
+	return a.router().Host(tpl, fn...)
+}
+
+// URL calls URL of the Router that serves the requests
+func (a *App) URL(name string, params ...string) (string, error) {
+	return a.router().URL(name, params...)
+}
+
+// Use calls Use of the Router that serves the requests
+func (a *App) Use(mf ...Middleware) {
+	a.router().Use(mf...)
+}
+
+// Pre calls Pre of the Router that serves the requests
+func (a *App) Pre(mf ...Middleware) {
+	a.router().Pre(mf...)
+}
+
+// Static calls Static of the Router that serves the requests
+func (a *App) Static(p string, fs http.FileSystem) {
+	a.router().Static(p, fs)
+}
+
+// Mount calls Mount of the Router that serves the requests
+func (a *App) Mount(p string, h http.Handler) {
+	a.router().Mount(p, h)
+}
+
+// Handle calls Handle of the Router that serves the requests
+func (a *App) Handle(method, p string, h HandlerFunc, mf ...Middleware) *Route {
+	return a.router().Handle(method, p, h, mf...)
+}
+
+// Remove calls Remove of the Router that serves the requests
+func (a *App) Remove(method, p string) bool {
+	return a.router().Remove(method, p)
+}
+
+// Routes calls Routes of the Router that serves the requests
+func (a *App) Routes() RouteTable {
+	return a.router().Routes()
+}
+
+// SetVersioning calls SetVersioning of the Router that serves the requests
+func (a *App) SetVersioning(v Versioning) {
+	a.router().SetVersioning(v)
+}
+
+// Version calls Version of the Router that serves the requests
+func (a *App) Version(version string, fn ...func(*Router)) *Router {
+	return a.router().Version(version, fn...)
+}
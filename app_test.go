@@ -1,6 +1,10 @@
 package otto
 
 import (
+	gocontext "context"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -55,3 +59,88 @@ func Test_UseAutoTLS(t *testing.T) {
 	time.Sleep(200 * time.Millisecond)
 	assert.NoError(t, app.Close(nil))
 }
+
+func Test_App_SwapRouter(t *testing.T) {
+	opts := NewOptions()
+	opts.StrictSlash = true
+	opts.StrictRoutes = true
+	app := New(opts)
+
+	started, done := make(chan struct{}), make(chan struct{})
+	app.GET("/", func(ctx Context) error {
+		close(started)
+		<-done
+		return ctx.String(200, "old")
+	})
+
+	r := NewRouter(false)
+	r.GET("/", func(ctx Context) error {
+		return ctx.String(200, "new")
+	})
+
+	old := app.Router
+	inflight := httptest.NewRecorder()
+	go func() {
+		<-started
+		assert.True(t, old == app.SwapRouter(r))
+		close(done)
+	}()
+	app.ServeHTTP(inflight, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "old", inflight.Body.String())
+
+	res := httptest.NewRecorder()
+	app.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "new", res.Body.String())
+
+	// the methods of the App change the Router that is serving
+	app.GET("/added", func(ctx Context) error {
+		return ctx.String(200, "added")
+	})
+
+	res = httptest.NewRecorder()
+	app.ServeHTTP(res, httptest.NewRequest("GET", "/added", nil))
+	assert.Equal(t, "added", res.Body.String())
+	assert.True(t, old == app.Router, "the Router the App was created with should not change")
+
+	// the options of the App are applied to the new Router
+	res = httptest.NewRecorder()
+	app.ServeHTTP(res, httptest.NewRequest("GET", "/added/", nil))
+	assert.Equal(t, 301, res.Code)
+	assert.Panics(t, func() { app.GET("/added", nil) })
+}
+
+func Test_App_Shutdown_Cancels_Context(t *testing.T) {
+	app := New(NewOptions())
+
+	started := make(chan struct{})
+	done := make(chan struct{})
+
+	app.GET("/", func(ctx Context) error {
+		close(started)
+		select {
+		case <-ctx.Context().Done():
+		case <-time.After(time.Second):
+			t.Error("context should be cancelled")
+		}
+		close(done)
+		return nil
+	})
+
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	defer cancel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err, "should not throw any error") {
+		return
+	}
+
+	s := app.server(ctx)
+	go s.Serve(ln)
+	defer s.Close()
+
+	go http.Get("http://" + ln.Addr().String())
+
+	<-started
+	cancel()
+	<-done
+}
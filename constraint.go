@@ -0,0 +1,101 @@
+package otto
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// constraints holds the patterns of the param types that can be
+// used in route paths, like "/users/{id:int}"
+var constraints = struct {
+	sync.RWMutex
+	patterns map[string]string
+}{
+	patterns: map[string]string{
+		"int":  `-?[0-9]+`,
+		"slug": `[a-z0-9]+(?:-[a-z0-9]+)*`,
+		"uuid": `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+		"date": `[0-9]{4}-[0-9]{2}-[0-9]{2}`,
+	},
+}
+
+// RegisterConstraint registers a param type that can be used in route paths.
+// The pattern is a regular expression without capturing groups,
+// RegisterConstraint("hex", "[0-9a-f]+") makes it possible to use "/{color:hex}"
+func RegisterConstraint(name, pattern string) error {
+	if name == "" {
+		return errors.New("constraint name cannot be empty")
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return errors.Wrapf(err, "invalid pattern for constraint '%s'", name)
+	}
+
+	if re.NumSubexp() > 0 {
+		return errors.Errorf("pattern for constraint '%s' cannot contain capturing groups", name)
+	}
+
+	constraints.Lock()
+	constraints.patterns[name] = pattern
+	constraints.Unlock()
+
+	return nil
+}
+
+// typeName matches a pattern that is the name of a param type
+var typeName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// expandConstraints replaces the param types in the template with the
+// pattern that was registered for the type. It panics if a pattern looks
+// like a type that is not registered, like "{id:integer}"
+func expandConstraints(tpl string) string {
+	if !strings.Contains(tpl, "{") {
+		return tpl
+	}
+
+	constraints.RLock()
+	defer constraints.RUnlock()
+
+	var b []byte
+	level, start := 0, 0
+	for i := 0; i < len(tpl); i++ {
+		switch tpl[i] {
+		case '{':
+			if level == 0 {
+				b = append(b, tpl[start:i]...)
+				start = i
+			}
+			level++
+		case '}':
+			level--
+			if level == 0 {
+				b = append(b, expandConstraint(tpl[start+1:i])...)
+				start = i + 1
+			}
+		}
+	}
+
+	return string(append(b, tpl[start:]...))
+}
+
+func expandConstraint(v string) string {
+	i := strings.Index(v, ":")
+	if i == -1 {
+		return "{" + v + "}"
+	}
+
+	name := v[i+1:]
+	if pattern, ok := constraints.patterns[name]; ok {
+		return "{" + v[:i] + ":" + pattern + "}"
+	}
+
+	if typeName.MatchString(name) {
+		panic(errors.Errorf("unknown constraint '%s' for param '%s'", name, v[:i]))
+	}
+
+	return "{" + v + "}"
+}
@@ -0,0 +1,155 @@
+package otto
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Constraints(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/users/{id:int}", func(ctx Context) error {
+		id, err := ctx.Params().Int("id")
+		if err != nil {
+			return err
+		}
+		return ctx.JSON(200, id)
+	})
+
+	r.GET("/posts/{slug:slug}", func(ctx Context) error {
+		return ctx.String(200, ctx.Params().String("slug"))
+	})
+
+	r.GET("/orders/{id:uuid}", func(ctx Context) error {
+		id, err := ctx.Params().UUID("id")
+		if err != nil {
+			return err
+		}
+		return ctx.String(200, id)
+	})
+
+	r.GET("/archive/{at:date}", func(ctx Context) error {
+		at, err := ctx.Params().Date("at")
+		if err != nil {
+			return err
+		}
+		return ctx.String(200, at.Format(time.RFC3339))
+	})
+
+	r.GET("/codes/{code:[a-z]{3}}", func(ctx Context) error {
+		return ctx.String(200, ctx.Params().String("code"))
+	})
+
+	table := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/users/12", 200, "12"},
+		{"/users/-1", 200, "-1"},
+		{"/users/abc", 404, ""},
+		{"/posts/hello-world", 200, "hello-world"},
+		{"/posts/Hello_World", 404, ""},
+		{"/orders/5F8D3C4A-1B2C-4D3E-8F9A-0B1C2D3E4F5A", 200, "5f8d3c4a-1b2c-4d3e-8f9a-0b1c2d3e4f5a"},
+		{"/orders/123", 404, ""},
+		{"/archive/2018-04-01", 200, "2018-04-01T00:00:00Z"},
+		{"/archive/2018-13-01", 400, ""},
+		{"/archive/yesterday", 404, ""},
+		{"/codes/abc", 200, "abc"},
+		{"/codes/abcd", 404, ""},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", tc.path, nil)
+		r.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.code, res.Code, tc.path)
+		if tc.body != "" {
+			assert.Equal(t, tc.body, res.Body.String(), tc.path)
+		}
+	}
+
+	routes := r.Routes()
+	assert.Equal(t, "/users/{id:int}", routes[len(routes)-1].Path)
+}
+
+func Test_Constraints_URL(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/users/{id:int}", func(ctx Context) error {
+		return ctx.NoContent()
+	}).Name("user")
+
+	u, err := r.URL("user", "id", "1")
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, "/users/1", u)
+
+	_, err = r.URL("user", "id", "abc")
+	assert.Error(t, err, "should throw error")
+}
+
+func Test_RegisterConstraint(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, RegisterConstraint("hex", "[0-9a-f]+"), "should not throw any error")
+	assert.Error(t, RegisterConstraint("", "[0-9a-f]+"), "should throw error")
+	assert.Error(t, RegisterConstraint("broken", "[0-9"), "should throw error")
+	assert.Error(t, RegisterConstraint("group", "([0-9])"), "should throw error")
+
+	r := NewRouter(false)
+
+	r.GET("/colors/{color:hex}", func(ctx Context) error {
+		return ctx.String(200, ctx.Params().String("color"))
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/colors/ff00aa", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "ff00aa", res.Body.String())
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/colors/red", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
+
+func Test_Constraints_Unknown(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	h := func(ctx Context) error {
+		return nil
+	}
+
+	assert.Panics(t, func() { r.GET("/u/{id:integer}", h) })
+	assert.Panics(t, func() { r.Host("{tenant:tenant}.example.com") })
+	assert.NotPanics(t, func() { r.GET("/u/{id:[0-9]+}", h) })
+	assert.NotPanics(t, func() { r.GET("/v/{version:v[0-9]}", h) })
+}
+
+func Test_Constraints_Host(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.Host("{tenant:slug}.example.com").GET("/", func(ctx Context) error {
+		return ctx.String(200, ctx.Params().String("tenant"))
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Host = "acme-inc.example.com"
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, "acme-inc", res.Body.String())
+
+	req = httptest.NewRequest("GET", "/", nil)
+	req.Host = "Acme_Inc.example.com"
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
@@ -1,23 +1,31 @@
 package otto
 
 import (
+	gocontext "context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
 
-	"github.com/gorilla/mux"
-
 	"github.com/pkg/errors"
 )
 
 // Context defines interface for otto Context
 type Context interface {
+	Context() gocontext.Context
+	SetContext(gocontext.Context)
 	JSON(int, interface{}) error
+	XML(int, interface{}) error
+	Negotiate(int, interface{}) error
 	HTML(int, string) error
+	Render(code int, name string, data interface{}) error
 	String(int, string) error
 	Error(int, error) error
+	Stream(code int, contentType string, r io.Reader) error
+	StreamFunc(code int, contentType string, fn func(w io.Writer) error) error
+	SSE() *EventStream
 	NoContent() error
 	Redirect(code int, location string) error
 	Request() *http.Request
@@ -27,20 +35,46 @@ type Context interface {
 	QueryString() string
 	Bind(interface{}) error
 	Params() Params
+	URL(name string, params ...string) (string, error)
+	Version() string
 	Set(key string, val interface{})
 	Get(key string) interface{}
 }
 
+// newContext creates the context of a request, the params of
+// the route are looked up into a buffer of the context itself
+func (r *Router) newContext(res http.ResponseWriter, req *http.Request) *context {
+	c := &context{req: req}
+	c.response.ResponseWriter = res
+	c.res = &c.response
+	c.params = c.buf[:0]
+	c.use(r)
+	return c
+}
+
+// use associates the context with the Router
+func (c *context) use(r *Router) {
+	c.router = r
+	c.version = r.version
+	c.charset = r.charset
+	c.bindFunc = r.bindFunc
+}
+
 // Store is a generic map
 type Store map[string]interface{}
 
 type context struct {
 	res      *Response
+	response Response
 	req      *http.Request
 	charset  string
 	query    url.Values
+	params   Params
 	bindFunc BindFunc
+	router   *Router
+	version  string
 	store    map[string]interface{}
+	buf      [8]Param
 }
 
 func (c *context) Request() *http.Request {
@@ -59,6 +93,14 @@ func (c *context) JSON(code int, val interface{}) error {
 	return c.render(code, "application/json", b)
 }
 
+func (c *context) XML(code int, val interface{}) error {
+	b, err := marshalXML(val)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse xml")
+	}
+	return c.render(code, MIMEApplicationXML, b)
+}
+
 func (c *context) HTML(code int, val string) error {
 	return c.render(code, "text/html", []byte(val))
 }
@@ -112,7 +154,34 @@ func (c *context) Bind(dest interface{}) error {
 }
 
 func (c *context) Params() Params {
-	return Params(mux.Vars(c.req))
+	return c.params
+}
+
+func (c *context) URL(name string, params ...string) (string, error) {
+	if c.router == nil {
+		return "", errors.New("context is not associated with a router")
+	}
+	return c.router.URL(name, params...)
+}
+
+// Version returns the API version of the group the route was registered on,
+// routes outside of version groups resolve the version from the request
+func (c *context) Version() string {
+	if c.version != "" || c.router == nil {
+		return c.version
+	}
+	return c.router.versioning.resolve(c.req)
+}
+
+// Context returns the context.Context of the request, which is cancelled
+// when the client disconnects or the App shuts down
+func (c *context) Context() gocontext.Context {
+	return c.req.Context()
+}
+
+// SetContext replaces the context.Context of the request
+func (c *context) SetContext(ctx gocontext.Context) {
+	c.req = c.req.WithContext(ctx)
 }
 
 func (c *context) Set(key string, val interface{}) {
@@ -122,6 +191,8 @@ func (c *context) Set(key string, val interface{}) {
 	c.store[key] = val
 }
 
+// Get returns the value that was stored with Set, values of
+// the context.Context of the request are read with Context
 func (c *context) Get(key string) interface{} {
 	return c.store[key]
 }
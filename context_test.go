@@ -2,6 +2,7 @@ package otto
 
 import (
 	"bytes"
+	gocontext "context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -9,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -217,4 +219,105 @@ func Test_Context_Set_Get(t *testing.T) {
 
 	assert.NotEmpty(t, c.store)
 	assert.Equal(t, 1, numb)
+	assert.Nil(t, c.Get("missing"))
+}
+
+func Test_Context_URL(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/users/{id}", func(ctx Context) error {
+		u, err := ctx.URL("user", "id", ctx.Params().String("id"))
+		if err != nil {
+			return err
+		}
+		return ctx.Redirect(301, u+"/profile")
+	}).Name("user")
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/users/1", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err := http.DefaultTransport.RoundTrip(req)
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, 301, res.StatusCode)
+	assert.Equal(t, "/users/1/profile", res.Header.Get(HeaderLocation))
+}
+
+type testContextKey string
+
+func Test_Context_Context(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.SetContext(gocontext.WithValue(ctx.Context(), testContextKey("user"), "otto"))
+			return next(ctx)
+		}
+	})
+
+	r.GET("/", func(ctx Context) error {
+		c := ctx.Context()
+		assert.NoError(t, c.Err())
+		assert.Equal(t, "otto", c.Value(testContextKey("user")))
+		assert.Equal(t, "otto", ctx.Request().Context().Value(testContextKey("user")))
+		return ctx.NoContent()
+	})
+
+	done := make(chan error, 1)
+
+	r.GET("/goroutine", func(ctx Context) error {
+		c := ctx.Context()
+		go func() {
+			<-c.Done()
+			done <- c.Err()
+		}()
+		return ctx.NoContent()
+	})
+
+	r.GET("/deadline", func(ctx Context) error {
+		_, ok := ctx.Context().Deadline()
+		assert.True(t, ok)
+		return ctx.NoContent()
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, 204, res.Code)
+
+	// the context.Context outlives the HandlerFunc
+	c, cancel := gocontext.WithCancel(gocontext.Background())
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/goroutine", nil).WithContext(c))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.Equal(t, gocontext.Canceled, err)
+	case <-time.After(time.Second):
+		t.Error("context should be cancelled")
+	}
+
+	c, cancel = gocontext.WithTimeout(gocontext.Background(), time.Minute)
+	defer cancel()
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/deadline", nil).WithContext(c))
+	assert.Equal(t, 204, res.Code)
+}
+
+func Test_Context_XML(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/", func(ctx Context) error {
+		return ctx.XML(200, user{Name: "otto"})
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", res.Header().Get(HeaderContentType))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n<user><name>otto</name></user>", res.Body.String())
 }
@@ -0,0 +1,66 @@
+package otto
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Deprecation describes a deprecated route
+type Deprecation struct {
+	// Since is when the route was deprecated, if it is
+	// zero the route is deprecated without a date
+	Since time.Time
+	// Sunset is when the route will stop responding, if there is a date
+	Sunset time.Time
+	// Link is the URL of the route that replaces the route
+	Link string
+}
+
+// Deprecate marks the route as deprecated. Responses of the route gets
+// the Deprecation header and the Sunset and Link headers if the
+// Deprecation has a sunset date or a link
+func (r *Route) Deprecate(d Deprecation) *Route {
+	r.router.mu.Lock()
+	r.deprecation = &d
+	r.router.mu.Unlock()
+	return r
+}
+
+// SetDeprecationHook sets a function that is called for every request that is
+// served by a deprecated route, like to count the calls to deprecated routes.
+// The hook is always set on the Router that all groups was created from, so
+// it is used by the routes of all groups
+func (r *Router) SetDeprecationHook(fn func(ctx Context, route *Route)) {
+	root := r.root()
+	r.mu.Lock()
+	root.onDeprecated = fn
+	r.mu.Unlock()
+}
+
+// deprecated adds the deprecation headers to the response
+// and calls the deprecation hook of the router
+func (r *Route) deprecated(c *context, d *Deprecation) {
+	h := c.res.Header()
+
+	if d.Since.IsZero() {
+		h.Set(HeaderDeprecation, "true")
+	} else {
+		h.Set(HeaderDeprecation, fmt.Sprintf("@%d", d.Since.Unix()))
+	}
+	if !d.Sunset.IsZero() {
+		h.Set(HeaderSunset, d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Link != "" {
+		h.Add(HeaderLink, fmt.Sprintf("<%s>; rel=\"successor-version\"", d.Link))
+	}
+
+	root := r.router.root()
+	r.router.mu.RLock()
+	fn := root.onDeprecated
+	r.router.mu.RUnlock()
+
+	if fn != nil {
+		fn(c, r)
+	}
+}
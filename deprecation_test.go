@@ -0,0 +1,67 @@
+package otto
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Route_Deprecate(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	h := func(ctx Context) error {
+		return ctx.NoContent()
+	}
+
+	since := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2019, 6, 30, 23, 59, 59, 0, time.FixedZone("CET", 3600))
+
+	r.GET("/v1/users", h).Deprecate(Deprecation{
+		Since:  since,
+		Sunset: sunset,
+		Link:   "/v2/users",
+	})
+	r.Group("/v1").GET("/posts", h).Deprecate(Deprecation{})
+	r.GET("/v2/users", h)
+
+	// the hook is used by groups that were created before it was set
+	calls := map[string]int{}
+	r.Group("/v3").SetDeprecationHook(func(ctx Context, route *Route) {
+		calls[route.Method+" "+route.Path]++
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/v1/users", nil))
+	assert.Equal(t, "@1514764800", res.Header().Get(HeaderDeprecation))
+	assert.Equal(t, "Sun, 30 Jun 2019 22:59:59 GMT", res.Header().Get(HeaderSunset))
+	assert.Equal(t, `</v2/users>; rel="successor-version"`, res.Header().Get(HeaderLink))
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("HEAD", "/v1/posts", nil))
+	assert.Equal(t, "true", res.Header().Get(HeaderDeprecation))
+	assert.Empty(t, res.Header().Get(HeaderSunset))
+	assert.Empty(t, res.Header().Get(HeaderLink))
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/v2/users", nil))
+	assert.Empty(t, res.Header().Get(HeaderDeprecation))
+
+	assert.Equal(t, map[string]int{"GET /v1/users": 1, "GET /v1/posts": 1}, calls)
+
+	routes := r.Routes()
+	assert.True(t, routes[1].Deprecated)
+	assert.Equal(t, "/v2/users", routes[1].Successor)
+	assert.True(t, sunset.Equal(*routes[1].Sunset))
+	assert.True(t, routes[0].Deprecated)
+	assert.Nil(t, routes[0].Sunset)
+	assert.False(t, routes[2].Deprecated)
+
+	var b bytes.Buffer
+	assert.NoError(t, routes.JSON(&b), "should not throw any error")
+	assert.Contains(t, b.String(), `"sunset": "2019-06-30T23:59:59+01:00"`)
+	assert.Contains(t, b.String(), `"successor": "/v2/users"`)
+}
@@ -1,8 +1,10 @@
 package otto
 
 import (
+	"encoding/xml"
 	"fmt"
-	"strings"
+	"html"
+	"mime"
 )
 
 // HTTPError a typed error returned by handlers
@@ -15,6 +17,23 @@ func (e HTTPError) Error() string {
 	return e.Err.Error()
 }
 
+// RouteConflict is the error used when a route matches the same
+// requests as a route that already is registered
+type RouteConflict struct {
+	Route    *Route
+	Existing *Route
+}
+
+func (e RouteConflict) Error() string {
+	verb := "overlaps with"
+	if e.Route.Method == e.Existing.Method && e.Route.Path == e.Existing.Path {
+		verb = "is already registered by"
+	}
+	return fmt.Sprintf("route %s %s (%s) %s %s %s (%s)",
+		e.Route.Method, e.Route.Path, funcName(e.Route.HandlerFunc), verb,
+		e.Existing.Method, e.Existing.Path, funcName(e.Existing.HandlerFunc))
+}
+
 // ErrorHandler defines the interface of a error handler
 type ErrorHandler func(int, error, Context) error
 
@@ -49,21 +68,37 @@ func (e ErrorHandlers) Copy() ErrorHandlers {
 	}
 }
 
-// DefaultErrorHandler will return the error as json
+// errorBody is the body of errors rendered by DefaultErrorHandler
+type errorBody struct {
+	XMLName xml.Name `json:"-" xml:"error"`
+	Error   string   `json:"error" xml:"message"`
+	Code    int      `json:"code" xml:"code"`
+}
+
+// DefaultErrorHandler will return the error as json, xml, html or plain text.
+// The format is negotiated from the Accept header, if the request does not
+// accept a specific format the format of the request body is used
 func DefaultErrorHandler(code int, err error, ctx Context) error {
-	ct := ctx.Request().Header.Get(HeaderContentType)
-	if ct == "" {
-		ct = ctx.Request().Header.Get(HeaderAccept)
+	req := ctx.Request()
+	msg := fmt.Sprintf("%+v", err)
+
+	offers := []string{MIMETextPlain, MIMEApplicationJSON, MIMEApplicationXML, MIMETextHTML}
+	if mt, _, err := mime.ParseMediaType(req.Header.Get(HeaderContentType)); err == nil {
+		for i, offer := range offers {
+			if offer == mt || offer == suffixType(mt) {
+				offers[0], offers[i] = offers[i], offers[0]
+			}
+		}
 	}
 
-	if strings.Contains(ct, "json") {
-		err = ctx.JSON(code, map[string]interface{}{
-			"error": fmt.Sprintf("%+v", err),
-			"code":  code,
-		})
-	} else {
-		err = ctx.String(code, fmt.Sprintf("%+v", err))
+	switch negotiate(req.Header.Get(HeaderAccept), offers) {
+	case MIMEApplicationJSON:
+		return ctx.JSON(code, errorBody{Error: msg, Code: code})
+	case MIMEApplicationXML:
+		return ctx.XML(code, errorBody{Error: msg, Code: code})
+	case MIMETextHTML:
+		return ctx.HTML(code, html.EscapeString(msg))
 	}
 
-	return err
+	return ctx.String(code, msg)
 }
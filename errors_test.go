@@ -205,3 +205,109 @@ func Test_Router_Error_Fail(t *testing.T) {
 	assert.Contains(t, string(b), "some error")
 	assert.Equal(t, 1, triggered)
 }
+
+func Test_Router_Error_Not_Found(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	triggered := false
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			triggered = true
+			return next(ctx)
+		}
+	})
+
+	r.SetErrorHandlers(map[int]ErrorHandler{
+		404: func(code int, err error, ctx Context) error {
+			return ctx.String(code, "custom "+err.Error())
+		},
+	})
+
+	r.GET("/asd", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/missing", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.Equal(t, "custom could not find /missing", string(b))
+	assert.True(t, triggered, "middleware should be triggered")
+}
+
+func Test_Router_Error_Method_Not_Allowed(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	triggered := false
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			triggered = true
+			return next(ctx)
+		}
+	})
+
+	r.GET("/asd", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	r.Group("/").POST("/asd", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	r.PUT("/other", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/asd", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+	assert.Equal(t, "GET, HEAD, OPTIONS, POST", res.Header.Get(HeaderAllow))
+	assert.Equal(t, "method DELETE is not allowed for /asd", string(b))
+	assert.True(t, triggered, "middleware should be triggered")
+}
+
+func Test_Router_Error_Default_Formats(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.POST("/asd", func(ctx Context) error {
+		return ctx.Error(400, errors.New("<bad> request"))
+	})
+
+	table := []struct {
+		accept string
+		ct     string
+		body   string
+	}{
+		{"", "", "<bad> request"},
+		{"application/xml", "", "<error><message>&lt;bad&gt; request</message><code>400</code></error>"},
+		{"text/html,application/xhtml+xml,*/*;q=0.8", "", "&lt;bad&gt; request"},
+		{"", "application/json; charset=utf-8", `{"error":"\u003cbad\u003e request","code":400}`},
+		{"*/*", "application/vnd.otto.v2+json", `{"error":"\u003cbad\u003e request","code":400}`},
+		{"text/plain", "application/json", "<bad> request"},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/asd", nil)
+		req.Header.Set(HeaderAccept, tc.accept)
+		req.Header.Set(HeaderContentType, tc.ct)
+		r.ServeHTTP(res, req)
+
+		assert.Equal(t, 400, res.Code, tc.accept+tc.ct)
+		assert.Contains(t, res.Body.String(), tc.body, tc.accept+tc.ct)
+	}
+}
@@ -6,18 +6,23 @@ const (
 	HeaderAcceptEncoding                = "Accept-Encoding"
 	HeaderAllow                         = "Allow"
 	HeaderAuthorization                 = "Authorization"
+	HeaderCacheControl                  = "Cache-Control"
 	HeaderContentDisposition            = "Content-Disposition"
 	HeaderContentEncoding               = "Content-Encoding"
 	HeaderContentLength                 = "Content-Length"
 	HeaderContentType                   = "Content-Type"
 	HeaderCookie                        = "Cookie"
+	HeaderDeprecation                   = "Deprecation"
 	HeaderSetCookie                     = "Set-Cookie"
 	HeaderIfModifiedSince               = "If-Modified-Since"
+	HeaderLastEventID                   = "Last-Event-ID"
 	HeaderLastModified                  = "Last-Modified"
+	HeaderLink                          = "Link"
 	HeaderLocation                      = "Location"
 	HeaderUpgrade                       = "Upgrade"
 	HeaderVary                          = "Vary"
 	HeaderWWWAuthenticate               = "WWW-Authenticate"
+	HeaderXAccelBuffering               = "X-Accel-Buffering"
 	HeaderXForwardedFor                 = "X-Forwarded-For"
 	HeaderXForwardedProto               = "X-Forwarded-Proto"
 	HeaderXForwardedProtocol            = "X-Forwarded-Protocol"
@@ -28,6 +33,7 @@ const (
 	HeaderXRequestID                    = "X-Request-ID"
 	HeaderXRequestedWith                = "X-Requested-With"
 	HeaderServer                        = "Server"
+	HeaderSunset                        = "Sunset"
 	HeaderOrigin                        = "Origin"
 	HeaderAccessControlRequestMethod    = "Access-Control-Request-Method"
 	HeaderAccessControlRequestHeaders   = "Access-Control-Request-Headers"
@@ -49,4 +55,5 @@ const (
 	MIMEMultipartForm   = "multipart/form-data"
 	MIMEApplicationForm = "application/x-www-form-urlencoded"
 	MIMEApplicationJSON = "application/json"
+	MIMETextEventStream = "text/event-stream"
 )
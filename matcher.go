@@ -0,0 +1,135 @@
+package otto
+
+import (
+	"mime"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// Matcher decides if a route can serve a request, in addition
+// to the method, host and path of the route
+type Matcher interface {
+	Match(req *http.Request) bool
+	String() string
+}
+
+// When creates a group with the prefix and host of the Router where the
+// routes only matches requests that matches all Matchers. Routes for the same
+// method and path are tried in the order they were registered and routes
+// without Matchers are tried last
+func (r *Router) When(m ...Matcher) *Router {
+	g := r.group(r.prefix, r.host, nil)
+	g.matchers = append(g.matchers[:len(g.matchers):len(g.matchers)], m...)
+	return g
+}
+
+// Query matches requests with the query param,
+// an empty value matches any value of the param
+func Query(key, value string) Matcher {
+	return queryMatcher{key: key, value: value}
+}
+
+type queryMatcher struct {
+	key, value string
+}
+
+func (m queryMatcher) Match(req *http.Request) bool {
+	values, ok := req.URL.Query()[m.key]
+	if !ok {
+		return false
+	}
+	return m.value == "" || contains(values, m.value)
+}
+
+func (m queryMatcher) String() string {
+	return "query " + m.key + "=" + m.value
+}
+
+// Header matches requests with the header,
+// an empty value matches any value of the header
+func Header(key, value string) Matcher {
+	return headerMatcher{key: textproto.CanonicalMIMEHeaderKey(key), value: value}
+}
+
+type headerMatcher struct {
+	key, value string
+}
+
+func (m headerMatcher) Match(req *http.Request) bool {
+	values, ok := req.Header[m.key]
+	if !ok {
+		return false
+	}
+	return m.value == "" || contains(values, m.value)
+}
+
+func (m headerMatcher) String() string {
+	return "header " + m.key + "=" + m.value
+}
+
+// ContentType matches requests with any of the media types in the Content-Type
+// header, params like charset are ignored. A type like "image/*" matches all
+// image types
+func ContentType(types ...string) Matcher {
+	m := make(contentTypeMatcher, len(types))
+	for i, t := range types {
+		m[i] = strings.ToLower(t)
+	}
+	return m
+}
+
+type contentTypeMatcher []string
+
+func (m contentTypeMatcher) Match(req *http.Request) bool {
+	mt, _, err := mime.ParseMediaType(req.Header.Get(HeaderContentType))
+	if err != nil {
+		return false
+	}
+
+	for _, t := range m {
+		if t == mt || strings.HasSuffix(t, "/*") && strings.HasPrefix(mt, t[:len(t)-1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m contentTypeMatcher) String() string {
+	return "content-type " + strings.Join(m, ", ")
+}
+
+// matches checks if the request matches all Matchers of the route
+func (r *Route) matches(req *http.Request) bool {
+	for _, m := range r.matchers {
+		if !m.Match(req) {
+			return false
+		}
+	}
+	return true
+}
+
+// matcherKey is used to compare the Matchers of routes
+func (r *Route) matcherKey() string {
+	return matcherKey(r.matchers)
+}
+
+func matcherKey(ms []Matcher) string {
+	keys := make([]string, len(ms))
+	for i, m := range ms {
+		keys[i] = m.String()
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "; ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package otto
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Router_When(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	handler := func(name string) HandlerFunc {
+		return func(ctx Context) error {
+			return ctx.String(200, name)
+		}
+	}
+
+	r.POST("/upload", handler("default"))
+	r.When(ContentType("multipart/form-data")).POST("/upload", handler("multipart"))
+	r.When(ContentType("image/*")).POST("/upload", handler("image"))
+	r.When(Query("format", "csv")).GET("/export", handler("csv"))
+	r.When(Query("format", "")).GET("/export", handler("any format"))
+	r.When(Header("x-api-version", "2")).Group("/api").GET("/users", handler("v2"))
+	r.GET("/api/users", handler("v1"))
+	r.When(Header("X-Debug", "")).GET("/debug", handler("debug"))
+
+	table := []struct {
+		method string
+		path   string
+		header string
+		value  string
+		code   int
+		body   string
+	}{
+		{"POST", "/upload", HeaderContentType, "multipart/form-data; boundary=otto", 200, "multipart"},
+		{"POST", "/upload", HeaderContentType, "IMAGE/PNG", 200, "image"},
+		{"POST", "/upload", HeaderContentType, "application/json", 200, "default"},
+		{"POST", "/upload", "", "", 200, "default"},
+		{"GET", "/export?format=csv", "", "", 200, "csv"},
+		{"GET", "/export?format=xml", "", "", 200, "any format"},
+		{"GET", "/export", "", "", 404, ""},
+		{"GET", "/api/users", "X-Api-Version", "2", 200, "v2"},
+		{"GET", "/api/users", "X-Api-Version", "1", 200, "v1"},
+		{"HEAD", "/debug", "X-Debug", "1", 200, ""},
+		{"GET", "/debug", "", "", 404, ""},
+		{"DELETE", "/debug", "", "", 405, ""},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.header != "" {
+			req.Header.Set(tc.header, tc.value)
+		}
+		r.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.code, res.Code, tc.method+" "+tc.path)
+		if tc.code == 200 {
+			assert.Equal(t, tc.body, res.Body.String(), tc.method+" "+tc.path)
+		}
+	}
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("DELETE", "/upload", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+	assert.Equal(t, "OPTIONS, POST", res.Header().Get(HeaderAllow))
+
+	var matchers []string
+	for _, ri := range r.Routes() {
+		if ri.Path == "/upload" {
+			matchers = append(matchers, strings.Join(ri.Matchers, ""))
+		}
+	}
+	assert.Contains(t, matchers, "content-type multipart/form-data")
+	assert.Contains(t, matchers, "")
+}
+
+func Test_Router_When_Conflicts(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+	r.SetStrictRoutes(true)
+
+	h := func(ctx Context) error {
+		return ctx.NoContent()
+	}
+
+	r.When(Query("a", "1"), Header("b", "2")).GET("/", h)
+	assert.NotPanics(t, func() { r.GET("/", h) })
+	assert.NotPanics(t, func() { r.When(Query("a", "2")).GET("/", h) })
+	assert.Panics(t, func() { r.When(Header("B", "2"), Query("a", "1")).GET("/", h) })
+
+	assert.True(t, r.When(Query("a", "2")).Remove("GET", "/"))
+	assert.False(t, r.When(Query("a", "2")).Remove("GET", "/"))
+	assert.True(t, r.Remove("GET", "/"))
+	assert.Len(t, r.Routes(), 1)
+}
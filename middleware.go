@@ -1,18 +1,24 @@
 package otto
 
-// Middleware defines the inteface for a otto Middleware
+// Middleware defines the inteface for a otto Middleware.
+//
+// Middleware runs in the order it was added, Router Middleware
+// runs first, then Group Middleware and last Route Middleware
 type Middleware func(HandlerFunc) HandlerFunc
 
+// middlewareStack keeps the last added Middleware first,
+// which makes the first added Middleware the outermost when wrapping
 type middlewareStack []Middleware
 
 func (m *middlewareStack) Add(mm ...Middleware) {
-	stack := append(middlewareStack{}, mm...)
+	stack := make(middlewareStack, 0, len(mm)+len(*m))
+	for i := len(mm) - 1; i >= 0; i-- {
+		stack = append(stack, mm[i])
+	}
 	*m = append(stack, *m...)
 }
 
-func (m middlewareStack) Handle(r Route) HandlerFunc {
-	h := r.HandlerFunc
-
+func (m middlewareStack) Handle(h HandlerFunc) HandlerFunc {
 	h = func(_ HandlerFunc) HandlerFunc {
 		return h
 	}(h)
@@ -73,3 +73,13 @@ func (grw *gzipResponseWriter) Write(b []byte) (int, error) {
 	}
 	return grw.Writer.Write(b)
 }
+
+// Flush sends the compressed data that is buffered to the client
+func (grw *gzipResponseWriter) Flush() {
+	if w, ok := grw.Writer.(*gzip.Writer); ok {
+		w.Flush()
+	}
+	if f, ok := grw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -69,3 +69,50 @@ func readGzipData(r io.Reader) []byte {
 	b, _ := ioutil.ReadAll(gr)
 	return b
 }
+
+func Test_Middleware_Compress_Stream(t *testing.T) {
+	t.Parallel()
+	r := otto.NewRouter(false)
+
+	r.Use(Compress())
+
+	next := make(chan struct{})
+
+	r.GET("/asd", func(ctx otto.Context) error {
+		return ctx.StreamFunc(200, "text/plain", func(w io.Writer) error {
+			for i := 0; i < 3; i++ {
+				fmt.Fprintf(w, "line %d\n", i)
+				<-next
+			}
+			return nil
+		})
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/asd", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	req.Header.Set(otto.HeaderAcceptEncoding, gzipSchema)
+
+	res, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err, "should not throw any error") {
+		return
+	}
+	defer res.Body.Close()
+	assert.Equal(t, gzipSchema, res.Header.Get(otto.HeaderContentEncoding))
+
+	gr, err := gzip.NewReader(res.Body)
+	if !assert.NoError(t, err, "should not throw any error") {
+		return
+	}
+
+	// each line is read before the next one is written
+	b := make([]byte, len("line 0\n"))
+	for i := 0; i < 3; i++ {
+		_, err := io.ReadFull(gr, b)
+		assert.NoError(t, err, "should not throw any error")
+		assert.Equal(t, fmt.Sprintf("line %d\n", i), string(b))
+		next <- struct{}{}
+	}
+}
@@ -0,0 +1,113 @@
+package otto
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func recordMiddleware(mu *sync.Mutex, calls *[]string, name string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			mu.Lock()
+			*calls = append(*calls, name)
+			mu.Unlock()
+			return next(ctx)
+		}
+	}
+}
+
+func Test_Middleware_Order(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var calls []string
+
+	r := NewRouter(false)
+	r.Use(recordMiddleware(&mu, &calls, "router1"), recordMiddleware(&mu, &calls, "router2"))
+
+	g := r.Group("/api")
+	g.Use(recordMiddleware(&mu, &calls, "group"))
+
+	g.GET("/asd", func(ctx Context) error {
+		return ctx.String(200, "asd")
+	}, recordMiddleware(&mu, &calls, "route1")).Use(recordMiddleware(&mu, &calls, "route2"))
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(fmt.Sprintf("%s/api/asd", ts.URL))
+	assert.NoError(t, err, "should not throw any error")
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, "asd", string(b))
+
+	assert.Equal(t, []string{"router1", "router2", "group", "route1", "route2"}, calls)
+}
+
+func Test_Middleware_Route_Does_Not_Leak(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var calls []string
+
+	r := NewRouter(false)
+	r.Use(recordMiddleware(&mu, &calls, "router"))
+
+	r.GET("/public", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	r.GET("/private", func(ctx Context) error {
+		return ctx.NoContent()
+	}, recordMiddleware(&mu, &calls, "auth"))
+
+	g := r.Group("/api")
+	g.Use(recordMiddleware(&mu, &calls, "group"))
+
+	r.GET("/other", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	table := []struct {
+		path  string
+		calls []string
+	}{
+		{"/public", []string{"router"}},
+		{"/private", []string{"router", "auth"}},
+		{"/other", []string{"router"}},
+	}
+
+	for _, tc := range table {
+		calls = nil
+		res, err := http.Get(fmt.Sprintf("%s%s", ts.URL, tc.path))
+		assert.NoError(t, err, "should not throw any error")
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Equal(t, tc.calls, calls, tc.path)
+	}
+}
+
+func Test_Middleware_Route_Can_Stop_Chain(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/asd", func(ctx Context) error {
+		return ctx.String(200, "asd")
+	}, func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			return ctx.Error(http.StatusUnauthorized, fmt.Errorf("unauthorized"))
+		}
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(fmt.Sprintf("%s/asd", ts.URL))
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
@@ -0,0 +1,193 @@
+package otto
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Mime types used by content negotiation
+const (
+	MIMEApplicationXML = "application/xml"
+	MIMETextPlain      = "text/plain"
+	MIMETextHTML       = "text/html"
+)
+
+// MarshalFunc marshals a value to a media type, like json.Marshal
+type MarshalFunc func(v interface{}) ([]byte, error)
+
+type marshaler struct {
+	mediaType string
+	fn        MarshalFunc
+}
+
+// marshalers are the MarshalFuncs of a Router in the
+// order they are preferred when the client has no preference
+type marshalers []marshaler
+
+func defaultMarshalers() marshalers {
+	return marshalers{
+		{MIMEApplicationJSON, json.Marshal},
+		{MIMEApplicationXML, marshalXML},
+		{MIMETextPlain, func(v interface{}) ([]byte, error) {
+			return []byte(fmt.Sprint(v)), nil
+		}},
+		{MIMETextHTML, func(v interface{}) ([]byte, error) {
+			return []byte(html.EscapeString(fmt.Sprint(v))), nil
+		}},
+	}
+}
+
+func (m marshalers) Copy() marshalers {
+	return append(marshalers{}, m...)
+}
+
+func (m marshalers) types() []string {
+	types := make([]string, len(m))
+	for i, mm := range m {
+		types[i] = mm.mediaType
+	}
+	return types
+}
+
+func (m marshalers) get(mediaType string) MarshalFunc {
+	for _, mm := range m {
+		if mm.mediaType == mediaType {
+			return mm.fn
+		}
+	}
+	return nil
+}
+
+// SetMarshalFunc sets the MarshalFunc for the media type that is used by
+// Context.Negotiate, like "application/yaml". JSON, XML, plain text and
+// HTML are supported by default
+func (r *Router) SetMarshalFunc(mediaType string, fn MarshalFunc) {
+	mediaType = strings.ToLower(mediaType)
+	for i, m := range r.marshalers {
+		if m.mediaType == mediaType {
+			r.marshalers[i].fn = fn
+			return
+		}
+	}
+	r.marshalers = append(r.marshalers, marshaler{mediaType, fn})
+}
+
+func (c *context) Negotiate(code int, val interface{}) error {
+	m := defaultMarshalers()
+	if c.router != nil {
+		m = c.router.marshalers
+	}
+
+	c.res.Header().Add(HeaderVary, HeaderAccept)
+
+	mediaType := negotiate(c.req.Header.Get(HeaderAccept), m.types())
+	if mediaType == "" {
+		return c.Error(406, errors.Errorf("could not render any of %s", c.req.Header.Get(HeaderAccept)))
+	}
+
+	b, err := m.get(mediaType)(val)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render %s", mediaType)
+	}
+
+	return c.render(code, mediaType, b)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	b, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// acceptRange is a media range of the Accept header
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses the media ranges of the Accept header,
+// ranges that can not be parsed are ignored
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: mt, q: q})
+	}
+	return ranges
+}
+
+// specificity returns how well the media range matches the media type,
+// 0 means that it does not match. A media type with a suffix, like
+// application/vnd.otto+json, matches the media type of the suffix
+func (a acceptRange) specificity(mediaType string) int {
+	switch {
+	case a.mediaType == mediaType:
+		return 3
+	case strings.HasSuffix(a.mediaType, "/*") && strings.HasPrefix(mediaType, a.mediaType[:len(a.mediaType)-1]):
+		return 2
+	case suffixType(a.mediaType) == mediaType:
+		return 2
+	case a.mediaType == "*/*":
+		return 1
+	}
+	return 0
+}
+
+// suffixType returns the media type of the suffix of the media
+// type, like application/json for application/vnd.otto+json
+func suffixType(mediaType string) string {
+	i := strings.LastIndexByte(mediaType, '+')
+	if i == -1 {
+		return ""
+	}
+	return "application/" + mediaType[i+1:]
+}
+
+// negotiate returns the offered media type that is preferred by the Accept
+// header, offers that comes first are used when the client has no preference.
+// An empty string is returned if the client accepts none of the offers
+func negotiate(accept string, offers []string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return offers[0]
+	}
+
+	preferred, preferredQ := "", 0.0
+	for _, offer := range offers {
+		best, q := 0, 0.0
+		for _, r := range ranges {
+			if s := r.specificity(offer); s > best {
+				best, q = s, r.q
+			}
+		}
+		if best > 0 && q > preferredQ {
+			preferred, preferredQ = offer, q
+		}
+	}
+
+	return preferred
+}
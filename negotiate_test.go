@@ -0,0 +1,85 @@
+package otto
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Negotiate(t *testing.T) {
+	t.Parallel()
+
+	offers := []string{MIMEApplicationJSON, MIMEApplicationXML, MIMETextPlain, MIMETextHTML}
+
+	table := []struct {
+		accept string
+		want   string
+	}{
+		{"", MIMEApplicationJSON},
+		{"*/*", MIMEApplicationJSON},
+		{"application/xml", MIMEApplicationXML},
+		{"text/*", MIMETextPlain},
+		{"text/html, application/xml;q=0.9, */*;q=0.8", MIMETextHTML},
+		{"application/json;q=0.5, application/xml", MIMEApplicationXML},
+		{"text/*;q=0.5, text/html;q=0.1", MIMETextPlain},
+		{"*/*, application/json;q=0", MIMEApplicationXML},
+		{"application/vnd.otto.v2+json", MIMEApplicationJSON},
+		{"image/png", ""},
+		{"application/xml;q=2, text/plain", MIMETextPlain},
+	}
+
+	for _, tc := range table {
+		assert.Equal(t, tc.want, negotiate(tc.accept, offers), tc.accept)
+	}
+}
+
+type user struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func (u user) String() string {
+	return "<" + u.Name + ">"
+}
+
+func Test_Context_Negotiate(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.SetMarshalFunc("text/csv", func(v interface{}) ([]byte, error) {
+		return []byte(fmt.Sprintf("name\n%s\n", v.(user).Name)), nil
+	})
+
+	r.GET("/user", func(ctx Context) error {
+		return ctx.Negotiate(200, user{Name: "otto"})
+	})
+
+	table := []struct {
+		accept string
+		code   int
+		ct     string
+		body   string
+	}{
+		{"", 200, "application/json; charset=utf-8", `{"name":"otto"}`},
+		{"application/xml", 200, "application/xml; charset=utf-8", `<?xml version="1.0" encoding="UTF-8"?>` + "\n<user><name>otto</name></user>"},
+		{"text/plain", 200, "text/plain; charset=utf-8", "<otto>"},
+		{"text/html", 200, "text/html; charset=utf-8", "&lt;otto&gt;"},
+		{"text/csv", 200, "text/csv; charset=utf-8", "name\notto"},
+		{"image/png", http.StatusNotAcceptable, "text/plain; charset=utf-8", "could not render any of image/png"},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/user", nil)
+		req.Header.Set(HeaderAccept, tc.accept)
+		r.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.code, res.Code, tc.accept)
+		assert.Equal(t, tc.ct, res.Header().Get(HeaderContentType), tc.accept)
+		assert.Equal(t, tc.body, strings.TrimSpace(res.Body.String()), tc.accept)
+		assert.Equal(t, HeaderAccept, res.Header().Get(HeaderVary), tc.accept)
+	}
+}
@@ -1,31 +1,117 @@
 package otto
 
 import (
+	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
+// Param is a url param
+type Param struct {
+	Key   string
+	Value string
+}
+
 // Params holds url params and provides
 // simple ways to parse the value to different types
-type Params map[string]string
+type Params []Param
+
+// Get returns the value of the param and if the param exists
+func (p Params) Get(key string) (string, bool) {
+	for i := range p {
+		if p[i].Key == key {
+			return p[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// Map returns the params as a map, like the Params
+// of earlier versions that was a map[string]string
+func (p Params) Map() map[string]string {
+	m := make(map[string]string, len(p))
+	for _, param := range p {
+		m[param.Key] = param.Value
+	}
+	return m
+}
 
 // String returns the value as string
 func (p Params) String(key string) string {
-	return p[key]
+	v, _ := p.Get(key)
+	return v
 }
 
-// Int returns the value as int
+// Int returns the value as int, the error is a HTTPError
+// with status code 400 if the value could not be parsed
 func (p Params) Int(key string) (int, error) {
-	vi, err := strconv.Atoi(p[key])
-	return vi, errors.Wrapf(err, "failed to parse '%v' to int", p[key])
+	v := p.String(key)
+	vi, err := strconv.Atoi(v)
+	return vi, paramError(err, "failed to parse '%v' to int", v)
 }
 
-// Bool returns the value as bool
+// Int64 returns the value as int64, the error is a HTTPError
+// with status code 400 if the value could not be parsed
+func (p Params) Int64(key string) (int64, error) {
+	v := p.String(key)
+	vi, err := strconv.ParseInt(v, 10, 64)
+	return vi, paramError(err, "failed to parse '%v' to int64", v)
+}
+
+// Bool returns the value as bool, the error is a HTTPError
+// with status code 400 if the value could not be parsed
 func (p Params) Bool(key string) (bool, error) {
-	vb, err := strconv.ParseBool(p[key])
-	return vb, errors.Wrapf(err, "failed to parse '%v' to bool", p[key])
+	v := p.String(key)
+	vb, err := strconv.ParseBool(v)
+	return vb, paramError(err, "failed to parse '%v' to bool", v)
+}
+
+// Date returns the value as time.Time parsed with the layout "2006-01-02",
+// the error is a HTTPError with status code 400 if the value could not be parsed
+func (p Params) Date(key string) (time.Time, error) {
+	v := p.String(key)
+	t, err := time.Parse("2006-01-02", v)
+	return t, paramError(err, "failed to parse '%v' to date", v)
+}
+
+// UUID returns the value as a lower case uuid, the error is a
+// HTTPError with status code 400 if the value is not a uuid
+func (p Params) UUID(key string) (string, error) {
+	v := p.String(key)
+	if !uuidRegexp.MatchString(v) {
+		return "", paramError(errors.New("invalid uuid"), "failed to parse '%v' to uuid", v)
+	}
+	return strings.ToLower(v), nil
+}
+
+// Slug returns the value as a slug, the error is a HTTPError
+// with status code 400 if the value is not a slug
+func (p Params) Slug(key string) (string, error) {
+	v := p.String(key)
+	if !slugRegexp.MatchString(v) {
+		return "", paramError(errors.New("invalid slug"), "failed to parse '%v' to slug", v)
+	}
+	return v, nil
+}
+
+var (
+	uuidRegexp = regexp.MustCompile("^" + constraints.patterns["uuid"] + "$")
+	slugRegexp = regexp.MustCompile("^" + constraints.patterns["slug"] + "$")
+)
+
+func paramError(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return HTTPError{
+		Code: http.StatusBadRequest,
+		Err:  errors.Wrapf(err, format, args...),
+	}
 }
 
 // ValueParams holds url.Values and provides
@@ -7,17 +7,17 @@ import (
 	"net/url"
 	"testing"
 
-	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_Params_String(t *testing.T) {
-	r := mux.NewRouter()
+	r := NewRouter(false)
 
-	r.HandleFunc("/{id}", func(_ http.ResponseWriter, req *http.Request) {
-		p := Params(mux.Vars(req))
+	r.GET("/{id}", func(ctx Context) error {
+		p := ctx.Params()
 
 		assert.Equal(t, "1", p.String("id"))
+		return nil
 	})
 
 	ts := httptest.NewServer(r)
@@ -29,16 +29,24 @@ func Test_Params_String(t *testing.T) {
 	assert.NoError(t, err, "should not throw any error")
 }
 
+func Test_Params_Map(t *testing.T) {
+	p := Params{{"id", "1"}, {"name", "otto"}}
+
+	assert.Equal(t, map[string]string{"id": "1", "name": "otto"}, p.Map())
+	assert.Equal(t, "1", p.Map()["id"])
+}
+
 func Test_Params_Int(t *testing.T) {
-	r := mux.NewRouter()
+	r := NewRouter(false)
 
-	r.HandleFunc("/{id}", func(_ http.ResponseWriter, req *http.Request) {
-		p := Params(mux.Vars(req))
+	r.GET("/{id}", func(ctx Context) error {
+		p := ctx.Params()
 
 		id, err := p.Int("id")
 		assert.NoError(t, err, "should not cast error")
 
 		assert.Equal(t, 1, id)
+		return nil
 	})
 
 	ts := httptest.NewServer(r)
@@ -51,15 +59,16 @@ func Test_Params_Int(t *testing.T) {
 }
 
 func Test_Params_Int_Not_Int(t *testing.T) {
-	r := mux.NewRouter()
+	r := NewRouter(false)
 
-	r.HandleFunc("/{id}", func(_ http.ResponseWriter, req *http.Request) {
-		p := Params(mux.Vars(req))
+	r.GET("/{id}", func(ctx Context) error {
+		p := ctx.Params()
 
 		_, err := p.Int("id")
 		assert.Error(t, err, "should cast error")
 
 		assert.Contains(t, err.Error(), "failed to parse 'a' to int")
+		return nil
 	})
 
 	ts := httptest.NewServer(r)
@@ -72,15 +81,16 @@ func Test_Params_Int_Not_Int(t *testing.T) {
 }
 
 func Test_Params_Bool(t *testing.T) {
-	r := mux.NewRouter()
+	r := NewRouter(false)
 
-	r.HandleFunc("/{id}", func(_ http.ResponseWriter, req *http.Request) {
-		p := Params(mux.Vars(req))
+	r.GET("/{id}", func(ctx Context) error {
+		p := ctx.Params()
 
 		b, err := p.Bool("id")
 		assert.NoError(t, err, "should not cast error")
 
 		assert.Equal(t, true, b)
+		return nil
 	})
 
 	ts := httptest.NewServer(r)
@@ -93,15 +103,16 @@ func Test_Params_Bool(t *testing.T) {
 }
 
 func Test_Params_Bool_Not_Bool(t *testing.T) {
-	r := mux.NewRouter()
+	r := NewRouter(false)
 
-	r.HandleFunc("/{id}", func(_ http.ResponseWriter, req *http.Request) {
-		p := Params(mux.Vars(req))
+	r.GET("/{id}", func(ctx Context) error {
+		p := ctx.Params()
 
 		_, err := p.Bool("id")
 		assert.Error(t, err, "should cast error")
 
 		assert.Contains(t, err.Error(), "failed to parse 'a' to bool")
+		return nil
 	})
 
 	ts := httptest.NewServer(r)
@@ -228,3 +239,45 @@ func Test_ValueParams_Bools_Not_Bool(t *testing.T) {
 	assert.Error(t, err, "should cast error")
 	assert.Contains(t, err.Error(), "failed to parse 'a' to bool")
 }
+
+func Test_Params_Typed(t *testing.T) {
+	p := Params{
+		{Key: "id", Value: "9223372036854775807"},
+		{Key: "at", Value: "2018-04-01"},
+		{Key: "uuid", Value: "5F8D3C4A-1B2C-4D3E-8F9A-0B1C2D3E4F5A"},
+		{Key: "slug", Value: "hello-world"},
+		{Key: "bad", Value: "Not A Value"},
+	}
+
+	i, err := p.Int64("id")
+	assert.NoError(t, err, "should not cast error")
+	assert.Equal(t, int64(9223372036854775807), i)
+
+	at, err := p.Date("at")
+	assert.NoError(t, err, "should not cast error")
+	assert.Equal(t, 2018, at.Year())
+
+	id, err := p.UUID("uuid")
+	assert.NoError(t, err, "should not cast error")
+	assert.Equal(t, "5f8d3c4a-1b2c-4d3e-8f9a-0b1c2d3e4f5a", id)
+
+	slug, err := p.Slug("slug")
+	assert.NoError(t, err, "should not cast error")
+	assert.Equal(t, "hello-world", slug)
+
+	for _, f := range []func(string) error{
+		func(k string) error { _, err := p.Int(k); return err },
+		func(k string) error { _, err := p.Int64(k); return err },
+		func(k string) error { _, err := p.Bool(k); return err },
+		func(k string) error { _, err := p.Date(k); return err },
+		func(k string) error { _, err := p.UUID(k); return err },
+		func(k string) error { _, err := p.Slug(k); return err },
+	} {
+		err := f("bad")
+		if assert.Error(t, err, "should cast error") {
+			httpErr, ok := err.(HTTPError)
+			assert.True(t, ok, "should be HTTPError")
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+		}
+	}
+}
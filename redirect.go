@@ -0,0 +1,124 @@
+package otto
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// redirectTarget holds the target of a route that redirects
+type redirectTarget struct {
+	to   string
+	code int
+}
+
+// Redirect registers a GET route for the path that redirects to another
+// path with the status code. The params of the path can be used in the
+// target, like Redirect("/old/{id}", "/new/{id}", 301). The query of the
+// request is kept unless the target has a query of its own, a target that
+// ends with "?" drops the query
+func (r *Router) Redirect(from, to string, code int) *Route {
+	if code < 300 || code > 308 {
+		panic(errors.Errorf("invalid redirect status code %d for %s", code, from))
+	}
+
+	tpl, query := to, ""
+	if i := strings.LastIndexByte(to, '?'); i > strings.LastIndexByte(to, '}') {
+		tpl, query = to[:i], to[i:]
+	}
+	tpl = expandConstraints(tpl)
+
+	tpl, err := r.checkRedirect(path.Join(r.prefix, from), tpl)
+	if err != nil {
+		panic(errors.Wrapf(err, "could not register redirect from %s to %s", from, to))
+	}
+
+	route := r.GET(from, func(ctx Context) error {
+		ps := ctx.Params()
+		pairs := make([]string, 0, len(ps)*2)
+		for _, p := range ps {
+			pairs = append(pairs, p.Key, p.Value)
+		}
+
+		location, err := buildPath(tpl, pairs)
+		if err != nil {
+			return errors.Wrapf(err, "could not redirect to %s", to)
+		}
+
+		switch raw := ctx.Request().URL.RawQuery; {
+		case query == "?":
+		case query != "":
+			location += query
+		case raw != "":
+			location += "?" + raw
+		}
+
+		return ctx.Redirect(code, location)
+	})
+	route.redirect = &redirectTarget{to: to, code: code}
+
+	return route
+}
+
+// checkRedirect checks that all params of the target are params of the path
+// or the host of the Router. A param of the target that is a catch-all of the
+// path is turned into a catch-all, like "/new/{path}" to "/new/*path"
+func (r *Router) checkRedirect(from, tpl string) (string, error) {
+	segs, err := parseTemplate(expandConstraints(from))
+	if err != nil {
+		return "", err
+	}
+
+	params := map[string]bool{}
+	catchAlls := map[string]bool{}
+	for _, seg := range segs {
+		params[seg.name] = true
+		for _, name := range seg.names {
+			params[name] = true
+		}
+		if seg.kind == catchAllSegment {
+			catchAlls[seg.name] = true
+		}
+	}
+
+	if r.host != "" {
+		_, names, err := compileHost(expandConstraints(r.host))
+		if err != nil {
+			return "", err
+		}
+		for _, name := range names {
+			params[name] = true
+		}
+	}
+
+	prefix, catchAll := splitCatchAll(tpl)
+	vars, err := templateVars(prefix)
+	if err != nil {
+		return "", err
+	}
+
+	names := []string{}
+	for _, v := range vars {
+		names = append(names, v.name)
+
+		if !catchAlls[v.name] {
+			continue
+		}
+		if catchAll != "" || v.pattern != "" || v.end != len(prefix) || !strings.HasSuffix(prefix[:v.start], "/") {
+			return "", errors.Errorf("catch-all '%s' can only be used as the last segment of the target", v.name)
+		}
+		tpl = prefix[:v.start] + "*" + v.name
+	}
+	if catchAll != "" {
+		names = append(names, catchAll)
+	}
+
+	for _, name := range names {
+		if !params[name] {
+			return "", errors.Errorf("param '%s' is not in the path", name)
+		}
+	}
+
+	return tpl, nil
+}
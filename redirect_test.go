@@ -0,0 +1,64 @@
+package otto
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Router_Redirect(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.Redirect("/old/{id:int}", "/new/{id}", http.StatusMovedPermanently)
+	r.Redirect("/legacy/*path", "/files/*path", http.StatusFound)
+	r.Redirect("/past/*path", "/files/{path}", http.StatusFound)
+	r.Redirect("/search", "/find?", http.StatusTemporaryRedirect)
+	r.Redirect("/docs", "https://docs.example.com/?ref=otto", http.StatusPermanentRedirect)
+	r.Group("/users/{user}").Redirect("/profile", "/profiles/{user}", http.StatusMovedPermanently)
+
+	table := []struct {
+		path     string
+		code     int
+		location string
+	}{
+		{"/old/1", 301, "/new/1"},
+		{"/old/1?q=otto&page=2", 301, "/new/1?q=otto&page=2"},
+		{"/legacy/a/b%20c.txt", 302, "/files/a/b%20c.txt"},
+		{"/past/a/b.txt", 302, "/files/a/b.txt"},
+		{"/search?q=otto", 307, "/find"},
+		{"/docs?q=otto", 308, "https://docs.example.com/?ref=otto"},
+		{"/users/otto/profile", 301, "/profiles/otto"},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest("GET", tc.path, nil))
+		assert.Equal(t, tc.code, res.Code, tc.path)
+		assert.Equal(t, tc.location, res.Header().Get(HeaderLocation), tc.path)
+	}
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/old/abc", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	routes := r.Routes()
+	assert.Equal(t, "/old/{id:int}", routes[2].Path)
+	assert.Equal(t, "/new/{id}", routes[2].Redirect)
+	assert.Equal(t, "redirect 301 /new/{id}", routes[2].Handler)
+}
+
+func Test_Router_Redirect_Invalid(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	assert.Panics(t, func() { r.Redirect("/old/{id}", "/new/{name}", 301) })
+	assert.Panics(t, func() { r.Redirect("/old/{id}", "/new/*path", 301) })
+	assert.Panics(t, func() { r.Redirect("/old", "/new", 200) })
+	assert.Panics(t, func() { r.Redirect("/old/*path", "/new/{path}/edit", 301) })
+	assert.Panics(t, func() { r.Redirect("/old/*path", "/new/{path:[a-z]+}", 301) })
+	assert.Panics(t, func() { r.Redirect("/old/*path", "/new/file-{path}", 301) })
+	assert.NotPanics(t, func() { r.Host("{tenant}.example.com").Redirect("/", "/tenants/{tenant}", 302) })
+}
@@ -0,0 +1,34 @@
+package otto
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Renderer renders the template with the name, it is used by Context.Render
+type Renderer interface {
+	Render(w io.Writer, name string, data interface{}, ctx Context) error
+}
+
+// SetRenderer sets the Renderer that is used by Context.Render. Groups
+// created after the Renderer is set will use it as well
+func (r *Router) SetRenderer(rn Renderer) {
+	r.renderer = rn
+}
+
+func (c *context) Render(code int, name string, data interface{}) error {
+	if c.router == nil || c.router.renderer == nil {
+		return errors.New("no renderer is set")
+	}
+
+	// the template is rendered to a buffer so that a template
+	// that fails does not leave a half written response
+	buf := new(bytes.Buffer)
+	if err := c.router.renderer.Render(buf, name, data, c); err != nil {
+		return errors.Wrapf(err, "failed to render %s", name)
+	}
+
+	return c.render(code, MIMETextHTML, buf.Bytes())
+}
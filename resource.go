@@ -0,0 +1,128 @@
+package otto
+
+import (
+	"path"
+	"strings"
+)
+
+// Lister is implemented by resources that can list items, GET /resources
+type Lister interface {
+	List(Context) error
+}
+
+// Shower is implemented by resources that can show an item, GET /resources/{id}
+type Shower interface {
+	Show(Context) error
+}
+
+// Creator is implemented by resources that can create items, POST /resources
+type Creator interface {
+	Create(Context) error
+}
+
+// Updater is implemented by resources that can replace an item, PUT /resources/{id}
+type Updater interface {
+	Update(Context) error
+}
+
+// Patcher is implemented by resources that can patch an item, PATCH /resources/{id}
+type Patcher interface {
+	Patch(Context) error
+}
+
+// Destroyer is implemented by resources that can delete an item, DELETE /resources/{id}
+type Destroyer interface {
+	Destroy(Context) error
+}
+
+// Resource holds the routes of a RESTful resource
+type Resource struct {
+	router *Router
+	name   string
+	param  string
+}
+
+// Resource registers RESTful routes for the handler. The routes depends on
+// which of the interfaces Lister, Shower, Creator, Updater, Patcher and
+// Destroyer the handler implements. The id of an item is available as the
+// param "id" and the routes are named after the resource, like "users.show"
+func (r *Router) Resource(p string, h interface{}, mf ...Middleware) *Resource {
+	return newResource(r, p, "", h, mf)
+}
+
+func newResource(r *Router, p, prefix string, h interface{}, mf []Middleware) *Resource {
+	res := &Resource{
+		router: r.Group(p),
+		name:   prefix + path.Base(path.Join("/", p)),
+	}
+	res.param = singular(path.Base(res.router.prefix)) + "_id"
+	res.router.Use(mf...)
+
+	if l, ok := h.(Lister); ok {
+		res.router.GET("/", l.List).Name(res.name + ".list")
+	}
+	if c, ok := h.(Creator); ok {
+		res.router.POST("/", c.Create).Name(res.name + ".create")
+	}
+	if s, ok := h.(Shower); ok {
+		res.router.GET("/{id}", s.Show).Name(res.name + ".show")
+	}
+	if u, ok := h.(Updater); ok {
+		res.router.PUT("/{id}", u.Update).Name(res.name + ".update")
+	}
+	if pa, ok := h.(Patcher); ok {
+		res.router.PATCH("/{id}", pa.Patch).Name(res.name + ".patch")
+	}
+	if d, ok := h.(Destroyer); ok {
+		res.router.DELETE("/{id}", d.Destroy).Name(res.name + ".destroy")
+	}
+
+	return res
+}
+
+// Resource registers a nested resource, the id of the parent resource
+// is available as a param named after the parent, like "user_id" for "/users"
+func (res *Resource) Resource(p string, h interface{}, mf ...Middleware) *Resource {
+	g := res.router.Group("/{" + res.param + "}")
+	return newResource(g, p, res.name+".", h, mf)
+}
+
+// Member registers an extra action on an item of the resource,
+// Member("POST", "/publish", h) maps to /resources/{id}/publish
+func (res *Resource) Member(method, p string, h HandlerFunc, mf ...Middleware) *Route {
+	return res.router.Handle(method, path.Join("/{id}", p), h, mf...).
+		Name(res.name + "." + actionName(p))
+}
+
+// Collection registers an extra action on the resource,
+// Collection("GET", "/search", h) maps to /resources/search
+func (res *Resource) Collection(method, p string, h HandlerFunc, mf ...Middleware) *Route {
+	return res.router.Handle(method, p, h, mf...).
+		Name(res.name + "." + actionName(p))
+}
+
+// Router returns the group that the routes of the resource are registered on
+func (res *Resource) Router() *Router {
+	return res.router
+}
+
+// Routes returns information about the routes of the resource
+func (res *Resource) Routes() RouteTable {
+	return res.router.Routes()
+}
+
+func actionName(p string) string {
+	return strings.Replace(strings.Trim(p, "/"), "/", ".", -1)
+}
+
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "ses"):
+		return strings.TrimSuffix(s, "es")
+	case strings.HasSuffix(s, "s"):
+		return strings.TrimSuffix(s, "s")
+	}
+	return s
+}
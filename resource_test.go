@@ -0,0 +1,126 @@
+package otto
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type usersResource struct{}
+
+func (usersResource) List(ctx Context) error {
+	return ctx.String(200, "list")
+}
+
+func (usersResource) Show(ctx Context) error {
+	return ctx.String(200, "show "+ctx.Params().String("id"))
+}
+
+func (usersResource) Create(ctx Context) error {
+	return ctx.String(201, "create")
+}
+
+func (usersResource) Update(ctx Context) error {
+	return ctx.String(200, "update "+ctx.Params().String("id"))
+}
+
+func (usersResource) Patch(ctx Context) error {
+	return ctx.String(200, "patch "+ctx.Params().String("id"))
+}
+
+func (usersResource) Destroy(ctx Context) error {
+	return ctx.String(200, "destroy "+ctx.Params().String("id"))
+}
+
+type postsResource struct{}
+
+func (postsResource) List(ctx Context) error {
+	return ctx.String(200, "posts of "+ctx.Params().String("user_id"))
+}
+
+func (postsResource) Show(ctx Context) error {
+	p := ctx.Params()
+	return ctx.String(200, "post "+p.String("id")+" of "+p.String("user_id"))
+}
+
+func Test_Router_Resource(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	users := r.Resource("/users", usersResource{})
+	users.Resource("/posts", postsResource{})
+
+	users.Member("POST", "/activate", func(ctx Context) error {
+		return ctx.String(200, "activate "+ctx.Params().String("id"))
+	})
+
+	users.Collection("GET", "/search", func(ctx Context) error {
+		return ctx.String(200, "search")
+	})
+
+	table := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{"GET", "/users", 200, "list"},
+		{"POST", "/users", 201, "create"},
+		{"GET", "/users/1", 200, "show 1"},
+		{"PUT", "/users/1", 200, "update 1"},
+		{"PATCH", "/users/1", 200, "patch 1"},
+		{"DELETE", "/users/1", 200, "destroy 1"},
+		{"POST", "/users/1/activate", 200, "activate 1"},
+		{"GET", "/users/search", 200, "search"},
+		{"GET", "/users/1/posts", 200, "posts of 1"},
+		{"GET", "/users/1/posts/2", 200, "post 2 of 1"},
+		{"DELETE", "/users/1/posts/2", 405, ""},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest(tc.method, tc.path, nil))
+
+		assert.Equal(t, tc.code, res.Code, tc.method+" "+tc.path)
+		if tc.body != "" {
+			assert.Equal(t, tc.body, res.Body.String(), tc.method+" "+tc.path)
+		}
+	}
+
+	u, err := r.URL("users.posts.show", "user_id", "1", "id", "2")
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, "/users/1/posts/2", u)
+
+	u, err = r.URL("users.activate", "id", "1")
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, "/users/1/activate", u)
+
+	assert.Len(t, users.Routes(), 10)
+}
+
+func Test_Router_Resource_Partial(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	triggered := false
+	r.Resource("/categories", postsResource{}, func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			triggered = true
+			return next(ctx)
+		}
+	}).Resource("/items", usersResource{})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("POST", "/categories", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/categories/1/items/2", nil))
+	assert.Equal(t, "show 2", res.Body.String())
+	assert.True(t, triggered, "middleware should be triggered")
+
+	_, err := r.URL("categories.items.show", "category_id", "1", "id", "2")
+	assert.NoError(t, err, "should not throw any error")
+}
@@ -1,6 +1,9 @@
 package otto
 
-import "net/http"
+import (
+	"net/http"
+	"strconv"
+)
 
 // Response that holds some information about the response
 type Response struct {
@@ -11,10 +14,18 @@ type Response struct {
 
 func (r *Response) Write(b []byte) (int, error) {
 	s, err := r.ResponseWriter.Write(b)
-	r.size = s
+	r.size += s
 	return s, err
 }
 
+// Flush sends the data that is buffered to the client,
+// if the underlying http.ResponseWriter supports it
+func (r *Response) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // WriteHeader writes the code to response writer
 // and stores the status code
 func (r *Response) WriteHeader(code int) {
@@ -26,3 +37,37 @@ func (r *Response) WriteHeader(code int) {
 func (r Response) Size() int {
 	return r.size
 }
+
+// headResponseWriter discards the body of a response and
+// sets the Content-Length to the size of the discarded body
+type headResponseWriter struct {
+	http.ResponseWriter
+	code int
+	size int
+}
+
+func (w *headResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	w.size += len(b)
+	return len(b), nil
+}
+
+// flush writes the header with the Content-Length
+// of the body that was written
+func (w *headResponseWriter) flush() {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	if w.size > 0 && w.Header().Get(HeaderContentLength) == "" {
+		w.Header().Set(HeaderContentLength, strconv.Itoa(w.size))
+	}
+	w.ResponseWriter.WriteHeader(w.code)
+}
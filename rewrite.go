@@ -0,0 +1,79 @@
+package otto
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RewriteRule rewrites the path of requests that matches the Pattern to the
+// Replacement. The Replacement can use the groups of the Pattern, like $1 or
+// ${name}, and can have a query that is added to the query of the request
+type RewriteRule struct {
+	Pattern     string
+	Replacement string
+}
+
+// Rewrite creates a Middleware that rewrites the path of requests with the
+// first rule that matches the path. Used with Router.Pre it changes which
+// route is used for the request. It panics if a Pattern is invalid
+func Rewrite(rules ...RewriteRule) Middleware {
+	res := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			panic(errors.Wrapf(err, "invalid rewrite pattern '%s'", rule.Pattern))
+		}
+		res[i] = re
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			u := ctx.Request().URL
+			for i, re := range res {
+				m := re.FindStringSubmatchIndex(u.Path)
+				if m == nil {
+					continue
+				}
+
+				p := string(re.ExpandString(nil, rules[i].Replacement, u.Path, m))
+				if err := rewrite(u, p); err != nil {
+					return ctx.Error(400, errors.Wrapf(err, "could not rewrite %s", u.Path))
+				}
+				break
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// rewrite sets the path of the url and adds the query of the path if it has one
+func rewrite(u *url.URL, p string) error {
+	q := ""
+	if i := strings.IndexByte(p, '?'); i != -1 {
+		p, q = p[:i], p[i+1:]
+	}
+
+	u.Path, u.RawPath = p, ""
+	if q == "" {
+		return nil
+	}
+
+	extra, err := url.ParseQuery(q)
+	if err != nil {
+		return err
+	}
+
+	values := u.Query()
+	for k, vs := range extra {
+		for _, v := range vs {
+			values.Add(k, v)
+		}
+	}
+	u.RawQuery = values.Encode()
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package otto
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Router_Pre(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	var order []string
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			order = append(order, "use")
+			return next(ctx)
+		}
+	})
+
+	// method override and lower case paths
+	r.Group("/api").Pre(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			order = append(order, "pre")
+			req := ctx.Request()
+			if m := req.Header.Get(HeaderXHTTPMethodOverride); m != "" && req.Method == "POST" {
+				req.Method = m
+			}
+			req.URL.Path = strings.ToLower(req.URL.Path)
+			return next(ctx)
+		}
+	})
+
+	r.DELETE("/users/{id}", func(ctx Context) error {
+		return ctx.String(200, "deleted "+ctx.Params().String("id"))
+	})
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/Users/1", nil)
+	req.Header.Set(HeaderXHTTPMethodOverride, "DELETE")
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "deleted 1", res.Body.String())
+	assert.Equal(t, []string{"pre", "use"}, order)
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/users/1", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+}
+
+func Test_Rewrite(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.Pre(Rewrite(
+		RewriteRule{Pattern: `^/blog/(\d+)/(?P<slug>[^/]+)$`, Replacement: "/posts/${slug}?id=$1"},
+		RewriteRule{Pattern: `^/old/(.*)$`, Replacement: "/new/$1"},
+		RewriteRule{Pattern: `^/old/.*$`, Replacement: "/never"},
+	))
+
+	r.GET("/posts/{slug}", func(ctx Context) error {
+		return ctx.String(200, ctx.Params().String("slug")+" "+ctx.QueryString())
+	})
+	r.GET("/new/*path", func(ctx Context) error {
+		return ctx.String(200, ctx.Params().String("path"))
+	})
+
+	table := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/blog/12/hello", 200, "hello id=12"},
+		{"/blog/12/hello?ref=feed", 200, "hello id=12&ref=feed"},
+		{"/old/a/b", 200, "a/b"},
+		{"/new/c", 200, "c"},
+		{"/blog/hello", 404, ""},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest("GET", tc.path, nil))
+
+		assert.Equal(t, tc.code, res.Code, tc.path)
+		if tc.code == 200 {
+			assert.Equal(t, tc.body, res.Body.String(), tc.path)
+		}
+	}
+
+	assert.Panics(t, func() { Rewrite(RewriteRule{Pattern: "(", Replacement: "/"}) })
+}
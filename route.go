@@ -2,9 +2,6 @@ package otto
 
 import (
 	"net/http"
-
-	"github.com/gorilla/mux"
-	"github.com/pkg/errors"
 )
 
 // HandlerFunc defines the interface for r Route HandlerFunc
@@ -12,41 +9,73 @@ type HandlerFunc func(Context) error
 
 // Route has information about the route
 type Route struct {
-	mux         *mux.Route
+	tpl         string
+	name        string
 	Path        string
+	Host        string
 	Method      string
 	HandlerFunc HandlerFunc
+	middleware  middlewareStack
 	router      *Router
-	charset     string
+	redirect    *redirectTarget
+	matchers    []Matcher
+	deprecation *Deprecation
+	detached    bool
 }
 
-func (r Route) ServeHTTP(res http.ResponseWriter, req *http.Request) {
-	ctx := &context{
-		res: &Response{
-			ResponseWriter: res,
-			size:           0,
-		},
-		req:      req,
-		charset:  r.charset,
-		bindFunc: r.router.bindFunc,
+// Name sets the name of the route, the name can be used
+// to build the URL of the route with Router.URL
+func (r *Route) Name(name string) *Route {
+	r.router.mu.Lock()
+	defer r.router.mu.Unlock()
+
+	// a duplicate that was not registered does not take the name
+	if r.detached {
+		r.name = name
+		return r
 	}
-	if err := r.router.middleware.Handle(r)(ctx); err != nil {
-		r.renderError(err, ctx)
+
+	if r.name != "" && r.router.namedRoutes[r.name] == r {
+		delete(r.router.namedRoutes, r.name)
 	}
+	r.name = name
+	r.router.namedRoutes[name] = r
+	return r
 }
 
-func (r Route) renderError(err error, ctx Context) {
-	code := 500
-	// check if err has underlying error of type HTTPError
-	if httpError, ok := errors.Cause(err).(HTTPError); ok {
-		code = httpError.Code
-	}
+// GetName returns the name of the route
+func (r *Route) GetName() string {
+	return r.name
+}
+
+// Use adds a Middleware to the route. Route Middleware runs after
+// the Middleware of the Router that the route was registered on
+func (r *Route) Use(mf ...Middleware) *Route {
+	r.router.mu.Lock()
+	r.middleware.Add(mf...)
+	r.router.mu.Unlock()
+	return r
+}
+
+func (r Route) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	c := r.router.newContext(res, req)
+	r.serve(c)
+}
 
-	h := r.router.errorHandlers.Get(code)
-	if err = h(code, err, ctx); err != nil {
-		// ErrorHandler returned error
-		http.Error(ctx.Response(), err.Error(), 500)
+// serve runs the route with the Middleware of the router and the route
+func (r *Route) serve(c *context) {
+	// the middleware and deprecation can be changed while serving
+	r.router.mu.RLock()
+	routerMiddleware, middleware, deprecation := r.router.middleware, r.middleware, r.deprecation
+	r.router.mu.RUnlock()
+
+	if r.router.version != "" && !r.router.versioning.Prefix {
+		r.router.versioning.vary(c.res.Header())
+	}
+	if deprecation != nil {
+		r.deprecated(c, deprecation)
 	}
+	r.router.serve(c, routerMiddleware.Handle(middleware.Handle(r.HandlerFunc)))
 }
 
 // Routes alias for slice of routes
@@ -55,7 +84,8 @@ type Routes []*Route
 func (r Routes) Len() int      { return len(r) }
 func (r Routes) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
 func (r Routes) Less(i, j int) bool {
-	x := r[i].Path // + r[i].Method
-	y := r[j].Path // + r[j].Method
-	return x < y
+	if r[i].Path == r[j].Path {
+		return r[i].Method < r[j].Method
+	}
+	return r[i].Path < r[j].Path
 }
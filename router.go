@@ -1,39 +1,78 @@
 package otto
 
 import (
+	"log"
 	"net/http"
 	"os"
 	"path"
+	"regexp"
 	"sort"
+	"strings"
+	"sync"
 
-	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 )
 
+// anyMethods are the methods used by Router.Any
+var anyMethods = []string{
+	"GET",
+	"POST",
+	"PUT",
+	"PATCH",
+	"DELETE",
+	"HEAD",
+	"OPTIONS",
+	"CONNECT",
+	"TRACE",
+}
+
 // Router handles all middleware, routes and error handlers
 type Router struct {
-	mux           *mux.Router
+	tree          *tree
+	mu            *sync.RWMutex
+	parent        *Router
+	groups        []*Router
+	host          string
+	hostRegexp    *regexp.Regexp
 	middleware    middlewareStack
+	pre           middlewareStack
 	prefix        string
 	routes        Routes
+	namedRoutes   map[string]*Route
 	strictSlash   bool
+	strictRoutes  bool
+	matchers      []Matcher
+	versioning    Versioning
+	version       string
+	versions      map[string]string
+	onDeprecated  func(Context, *Route)
+	logf          func(string, ...interface{})
 	errorHandlers ErrorHandlers
 	bindFunc      BindFunc
+	marshalers    marshalers
+	renderer      Renderer
+	charset       string
 }
 
 // NewRouter creates a new Router with some default values
 func NewRouter(strictSlash bool) *Router {
 	return &Router{
-		mux:         mux.NewRouter().StrictSlash(strictSlash),
+		tree:        newTree(),
+		mu:          &sync.RWMutex{},
 		middleware:  middlewareStack{},
 		prefix:      "/",
 		routes:      Routes{},
+		namedRoutes: map[string]*Route{},
 		strictSlash: strictSlash,
+		logf:        log.Printf,
 		errorHandlers: ErrorHandlers{
 			DefaultHandler: DefaultErrorHandler,
 			Handlers:       map[int]ErrorHandler{},
 		},
-		bindFunc: DefaultBinder,
+		bindFunc:   DefaultBinder,
+		marshalers: defaultMarshalers(),
+		charset:    "utf-8",
+		versioning: Versioning{Header: "Accept-Version"},
 	}
 }
 
@@ -42,65 +81,414 @@ func (r *Router) SetErrorHandlers(eh map[int]ErrorHandler) {
 	r.errorHandlers.Handlers = eh
 }
 
-// GET maps an "GET" request to the path and handler
-func (r *Router) GET(p string, h HandlerFunc) {
-	r.addRoute("GET", p, h)
+// GET maps an "GET" request to the path and handler,
+// the Middleware will only be used by the route
+func (r *Router) GET(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return r.Handle("GET", p, h, mf...)
 }
 
-// POST maps an "POST" request to the path and handler
-func (r *Router) POST(p string, h HandlerFunc) {
-	r.addRoute("POST", p, h)
+// POST maps an "POST" request to the path and handler,
+// the Middleware will only be used by the route
+func (r *Router) POST(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return r.Handle("POST", p, h, mf...)
 }
 
-// PUT maps an "PUT" request to the path and handler
-func (r *Router) PUT(p string, h HandlerFunc) {
-	r.addRoute("PUT", p, h)
+// PUT maps an "PUT" request to the path and handler,
+// the Middleware will only be used by the route
+func (r *Router) PUT(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return r.Handle("PUT", p, h, mf...)
 }
 
-// DELETE maps an "DELETE" request to the path and handler
-func (r *Router) DELETE(p string, h HandlerFunc) {
-	r.addRoute("DELETE", p, h)
+// DELETE maps an "DELETE" request to the path and handler,
+// the Middleware will only be used by the route
+func (r *Router) DELETE(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return r.Handle("DELETE", p, h, mf...)
 }
 
-// OPTIONS maps an "OPTIONS" request to the path and handler
-func (r *Router) OPTIONS(p string, h HandlerFunc) {
-	r.addRoute("OPTIONS", p, h)
+// OPTIONS maps an "OPTIONS" request to the path and handler,
+// the Middleware will only be used by the route
+func (r *Router) OPTIONS(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return r.Handle("OPTIONS", p, h, mf...)
 }
 
-// HEAD maps an "HEAD" request to the path and handler
-func (r *Router) HEAD(p string, h HandlerFunc) {
-	r.addRoute("HEAD", p, h)
+// HEAD maps an "HEAD" request to the path and handler,
+// the Middleware will only be used by the route
+func (r *Router) HEAD(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return r.Handle("HEAD", p, h, mf...)
 }
 
-// PATCH maps an "PATCH" request to the path and handler
-func (r *Router) PATCH(p string, h HandlerFunc) {
-	r.addRoute("PATCH", p, h)
+// PATCH maps an "PATCH" request to the path and handler,
+// the Middleware will only be used by the route
+func (r *Router) PATCH(p string, h HandlerFunc, mf ...Middleware) *Route {
+	return r.Handle("PATCH", p, h, mf...)
 }
 
-// Group creates a new Router with a prefix for all routes
-func (r *Router) Group(p string) *Router {
-	return &Router{
-		mux:           r.mux,
-		prefix:        p,
+// SetBinder sets the BindFunc that is used by Context.Bind
+func (r *Router) SetBinder(b BindFunc) {
+	r.bindFunc = b
+}
+
+// SetCharset sets the charset that is used in the Content-Type
+// header when rendering responses
+func (r *Router) SetCharset(charset string) {
+	r.charset = charset
+}
+
+// SetStrictRoutes decides what happens when a route is registered with
+// the same method as a route that matches the same paths. In strict mode
+// Handle panics with a RouteConflict, otherwise a warning is logged and the
+// route that was registered first is used. A route with the same path as
+// the route that was registered first is not added, Handle returns a route
+// that is never served and does not change the route that was registered
+// first
+func (r *Router) SetStrictRoutes(strict bool) {
+	r.strictRoutes = strict
+}
+
+// Match maps requests with any of the methods to the path and handler,
+// a Route is registered for every method
+func (r *Router) Match(methods []string, p string, h HandlerFunc, mf ...Middleware) Routes {
+	routes := make(Routes, 0, len(methods))
+	for _, method := range methods {
+		routes = append(routes, r.Handle(method, p, h, mf...))
+	}
+	return routes
+}
+
+// Any maps requests with any of the standard HTTP methods to the path and handler
+func (r *Router) Any(p string, h HandlerFunc, mf ...Middleware) Routes {
+	return r.Match(anyMethods, p, h, mf...)
+}
+
+// Group creates a new Router with a prefix for all routes.
+// The prefix is joined with the prefix of the parent Router and the
+// group inherits middleware, error handlers, binder and charset from it.
+// If fn is provided it will be called with the new group, which
+// makes it possible to declare the routes of the group in a block
+func (r *Router) Group(p string, fn ...func(*Router)) *Router {
+	return r.group(path.Join(r.prefix, p), r.host, fn)
+}
+
+// Host creates a new Router where all routes only match requests for the
+// host. The host can have variables like "{tenant}.example.com" which
+// will be available in Context.Params. The group works like a group
+// created by Group and keeps the prefix of the parent Router
+func (r *Router) Host(tpl string, fn ...func(*Router)) *Router {
+	return r.group(r.prefix, tpl, fn)
+}
+
+func (r *Router) group(prefix, host string, fn []func(*Router)) *Router {
+	g := &Router{
+		tree:          r.tree,
+		mu:            r.mu,
+		parent:        r,
+		host:          host,
+		hostRegexp:    r.hostRegexp,
+		prefix:        prefix,
 		routes:        Routes{},
+		namedRoutes:   r.namedRoutes,
+		strictSlash:   r.strictSlash,
+		strictRoutes:  r.strictRoutes,
+		matchers:      r.matchers,
+		versioning:    r.versioning,
+		version:       r.version,
+		logf:          r.logf,
 		middleware:    r.middleware.Copy(),
 		errorHandlers: r.errorHandlers.Copy(),
+		bindFunc:      r.bindFunc,
+		marshalers:    r.marshalers.Copy(),
+		renderer:      r.renderer,
+		charset:       r.charset,
+	}
+
+	if host != r.host {
+		re, _, err := compileHost(expandConstraints(host))
+		if err != nil {
+			panic(err)
+		}
+		g.hostRegexp = re
+	}
+
+	r.mu.Lock()
+	r.groups = append(r.groups, g)
+	r.mu.Unlock()
+
+	for _, f := range fn {
+		f(g)
+	}
+
+	return g
+}
+
+// root returns the Router that all groups was created from
+func (r *Router) root() *Router {
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
+}
+
+// lookup finds the most specific group that would
+// have handled the request if a route had matched
+func (r *Router) lookup(req *http.Request) *Router {
+	var best *Router
+	for _, g := range r.groups {
+		if !g.matches(req) {
+			continue
+		}
+		if best == nil || g.moreSpecific(best) {
+			best = g
+		}
+	}
+
+	if best == nil {
+		return r
 	}
+
+	return best.lookup(req)
+}
+
+// moreSpecific reports if the Router is more specific than o,
+// groups with a host are more specific than groups without
+func (r *Router) moreSpecific(o *Router) bool {
+	if (r.host != "") != (o.host != "") {
+		return r.host != ""
+	}
+	return len(r.prefix) > len(o.prefix)
+}
+
+// matches checks if the request matches the host and prefix of the Router
+func (r *Router) matches(req *http.Request) bool {
+	if r.hostRegexp != nil && !r.hostRegexp.MatchString(hostname(req)) {
+		return false
+	}
+
+	p := req.URL.Path
+	return r.prefix == "/" || p == r.prefix || strings.HasPrefix(p, r.prefix+"/")
+}
+
+// URL builds the path of the route registered with the provided name.
+// Params are given as key value pairs, like "id", "1"
+func (r *Router) URL(name string, params ...string) (string, error) {
+	r.mu.RLock()
+	route, ok := r.namedRoutes[name]
+	r.mu.RUnlock()
+	if !ok {
+		return "", errors.Errorf("could not find route with name '%s'", name)
+	}
+
+	u, err := buildPath(route.tpl, params)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to build url for route '%s'", name)
+	}
+
+	return u, nil
 }
 
 // Use adds a Middleware to the router
 func (r *Router) Use(mf ...Middleware) {
+	r.mu.Lock()
 	r.middleware.Add(mf...)
+	r.mu.Unlock()
+}
+
+// wrap wraps the HandlerFunc with the Middleware of the Router,
+// the Middleware can be changed while requests are served
+func (r *Router) wrap(h HandlerFunc) HandlerFunc {
+	r.mu.RLock()
+	m := r.middleware
+	r.mu.RUnlock()
+	return m.Handle(h)
+}
+
+// Pre adds a Middleware that runs before the route is matched. It sees the
+// request before the path is cleaned and can change the method and path
+// of the request to change which route is used. Pre Middleware is always
+// added to the Router that all groups was created from
+func (r *Router) Pre(mf ...Middleware) {
+	r.root().pre.Add(mf...)
 }
 
 // Static serves static files like javascript, css and html files
 func (r *Router) Static(p string, fs http.FileSystem) {
 	p = path.Join(r.prefix, p)
-	r.mux.PathPrefix(p).Handler(http.StripPrefix(p, r.serveFiles(fs)))
+	h := http.StripPrefix(p, r.serveFiles(fs))
+	r.handlePrefix(p, func(c *context) {
+		h.ServeHTTP(c.res.ResponseWriter, c.req)
+	})
+}
+
+// Mount serves the http.Handler for all requests with a path that
+// starts with the prefix, the prefix is stripped from the path before
+// the handler is called. The Middleware of the Router is used
+func (r *Router) Mount(p string, h http.Handler) {
+	p = path.Join(r.prefix, p)
+	hf := WrapHandler(http.StripPrefix(p, h))
+	r.handlePrefix(p, func(c *context) {
+		r.serve(c, r.wrap(hf))
+	})
+}
+
+// handlePrefix uses the handler for the path and all paths below it
+func (r *Router) handlePrefix(p string, h func(*context)) {
+	err := r.tree.update(func(tb *table) error {
+		n := tb.root
+		if p != "/" {
+			var err error
+			if n, err = tb.insert(expandConstraints(r.host), expandConstraints(p)); err != nil {
+				return err
+			}
+			n.handler = h
+		}
+		n.child(segment{kind: catchAllSegment}).handler = h
+		return nil
+	})
+	if err != nil {
+		panic(errors.Wrapf(err, "could not register prefix %s", p))
+	}
 }
 
 func (r *Router) ServeHTTP(res http.ResponseWriter, req *http.Request) {
-	r.mux.ServeHTTP(res, req)
+	c := r.newContext(res, req)
+
+	root := r.root()
+	if len(root.pre) == 0 {
+		r.route(c)
+		return
+	}
+
+	root.serve(c, root.pre.Handle(func(ctx Context) error {
+		r.route(c)
+		return nil
+	}))
+}
+
+// route finds the route that matches the request and serves it
+func (r *Router) route(c *context) {
+	res, req := c.res, c.req
+
+	p := req.URL.Path
+	if cp := cleanPath(p); cp != p {
+		redirect(res, req, cp)
+		return
+	}
+
+	// the table is loaded once so the request is served by
+	// the same routes even if they are changed meanwhile
+	tb := r.tree.load()
+
+	host := ""
+	if len(tb.hosts) > 0 {
+		host = hostname(req)
+	}
+
+	n, first := tb.lookup(host, p, req, &c.params)
+	if n != nil {
+		n.serve(c)
+		return
+	}
+
+	root := r.root()
+	if first != nil {
+		root.methodNotAllowed(c, tb)
+		return
+	}
+
+	// requests without a version in the path use the default version
+	for _, vp := range root.defaultVersionPaths(p) {
+		if n, _ := tb.lookup(host, vp, req, &c.params); n != nil {
+			n.serve(c)
+			return
+		}
+	}
+
+	if root.strictSlash && p != "/" {
+		sp := p + "/"
+		if strings.HasSuffix(p, "/") {
+			sp = strings.TrimSuffix(p, "/")
+		}
+
+		if n, first := tb.lookup(host, sp, req, &c.params); n != nil || first != nil {
+			redirect(res, req, sp)
+			return
+		}
+	}
+
+	root.notFound(c)
+}
+
+// serve runs the HandlerFunc with the Context and
+// renders the error if the HandlerFunc returns one
+func (r *Router) serve(c *context, h HandlerFunc) {
+	c.use(r)
+	if err := h(c); err != nil {
+		r.renderError(err, c)
+	}
+}
+
+func (r *Router) renderError(err error, ctx Context) {
+	code := 500
+	// check if err has underlying error of type HTTPError
+	if httpError, ok := errors.Cause(err).(HTTPError); ok {
+		code = httpError.Code
+	}
+
+	h := r.errorHandlers.Get(code)
+	if err = h(code, err, ctx); err != nil {
+		// ErrorHandler returned error
+		http.Error(ctx.Response(), err.Error(), 500)
+	}
+}
+
+// notFound is used when no route matches the request, the
+// error handlers of the most specific group are used
+func (r *Router) notFound(c *context) {
+	r.mu.RLock()
+	g := r.lookup(c.req)
+	r.mu.RUnlock()
+
+	g.serve(c, g.wrap(func(ctx Context) error {
+		return ctx.Error(http.StatusNotFound, errors.Errorf("could not find %s", ctx.Request().URL))
+	}))
+}
+
+// methodNotAllowed is used when the path of a route matches the request
+// but the method does not, the Allow header lists the methods that would match.
+// OPTIONS requests are answered with the Allow header
+func (r *Router) methodNotAllowed(c *context, tb *table) {
+	allow := strings.Join(allowedMethods(tb, c.req), ", ")
+
+	r.mu.RLock()
+	g := r.lookup(c.req)
+	r.mu.RUnlock()
+
+	g.serve(c, g.wrap(func(ctx Context) error {
+		ctx.Response().Header().Set(HeaderAllow, allow)
+		if ctx.Request().Method == "OPTIONS" {
+			return ctx.NoContent()
+		}
+		err := errors.Errorf("method %s is not allowed for %s", ctx.Request().Method, ctx.Request().URL)
+		return ctx.Error(http.StatusMethodNotAllowed, err)
+	}))
+}
+
+// allowedMethods returns the methods of all routes that would match
+// the request if the method of the request was different, HEAD is
+// allowed if GET is and OPTIONS is always allowed
+func allowedMethods(tb *table, req *http.Request) []string {
+	seen := tb.methods(hostname(req), req.URL.Path)
+	seen["OPTIONS"] = true
+	if seen["GET"] {
+		seen["HEAD"] = true
+	}
+
+	methods := make([]string, 0, len(seen))
+	for m := range seen {
+		methods = append(methods, m)
+	}
+
+	sort.Strings(methods)
+	return methods
 }
 
 func (r *Router) serveFiles(fs http.FileSystem) http.HandlerFunc {
@@ -109,13 +497,7 @@ func (r *Router) serveFiles(fs http.FileSystem) http.HandlerFunc {
 
 		if _, err := fs.Open(path.Clean(req.URL.Path)); err != nil {
 			if os.IsNotExist(err) {
-				ctx := &context{
-					res: &Response{
-						ResponseWriter: res,
-						size:           0,
-					},
-					req: req,
-				}
+				ctx := r.newContext(res, req)
 				h := r.errorHandlers.Get(404)
 				if err = h(404, errors.Errorf("could not find %s", req.URL), ctx); err != nil {
 					http.Error(res, err.Error(), 500)
@@ -130,19 +512,106 @@ func (r *Router) serveFiles(fs http.FileSystem) http.HandlerFunc {
 	}
 }
 
-func (r *Router) addRoute(method, p string, h HandlerFunc) {
+// Handle maps a request with the method to the path and handler, the method
+// can be any method, including extension methods like PROPFIND or PURGE
+func (r *Router) Handle(method, p string, h HandlerFunc, mf ...Middleware) *Route {
 
 	p = path.Join(r.prefix, p)
 
 	route := &Route{
 		Method:      method,
 		Path:        p,
+		Host:        r.host,
+		tpl:         expandConstraints(p),
 		HandlerFunc: h,
+		middleware:  middlewareStack{},
 		router:      r,
-		charset:     "utf-8",
+		matchers:    r.matchers,
+	}
+
+	route.Use(mf...)
+
+	host := expandConstraints(r.host)
+
+	var duplicate bool
+	err := r.tree.update(func(tb *table) error {
+		n, err := tb.insert(host, route.tpl)
+		if err != nil {
+			return errors.Wrapf(err, "could not register route %s %s", method, p)
+		}
+
+		if c := tb.conflict(host, route.tpl, route); c != nil {
+			err := RouteConflict{Route: route, Existing: c}
+			if r.strictRoutes {
+				return err
+			}
+			r.logf("otto: warning: %s", err)
+		}
+
+		duplicate = n.add(route) != nil
+		return nil
+	})
+	if err != nil {
+		panic(err)
 	}
 
-	route.mux = r.mux.Handle(p, route).Methods(method)
-	r.routes = append(r.routes, route)
-	sort.Sort(r.routes)
+	// the route is a duplicate that is never served
+	if duplicate {
+		route.detached = true
+		return route
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// report the route to the group and all its parents
+	for g := r; g != nil; g = g.parent {
+		g.routes = append(g.routes, route)
+		sort.Sort(g.routes)
+	}
+
+	return route
+}
+
+// Remove removes the route with the method, path and Matchers of the Router
+// from a Router that can be serving requests. Requests that already matched
+// the route are finished by it. It reports if a route was removed
+func (r *Router) Remove(method, p string) bool {
+	p = path.Join(r.prefix, p)
+	key := matcherKey(r.matchers)
+
+	var route *Route
+	r.tree.update(func(tb *table) error {
+		route = tb.remove(expandConstraints(r.host), expandConstraints(p), method, key)
+		return nil
+	})
+
+	if route == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for g := route.router; g != nil; g = g.parent {
+		for i, gr := range g.routes {
+			if gr == route {
+				g.routes = append(g.routes[:i:i], g.routes[i+1:]...)
+				break
+			}
+		}
+	}
+
+	if route.name != "" && r.namedRoutes[route.name] == route {
+		delete(r.namedRoutes, route.name)
+	}
+
+	return true
+}
+
+func redirect(res http.ResponseWriter, req *http.Request, p string) {
+	u := *req.URL
+	u.Path = p
+	res.Header().Set(HeaderLocation, u.String())
+	res.WriteHeader(http.StatusMovedPermanently)
 }
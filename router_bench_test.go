@@ -0,0 +1,77 @@
+package otto
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type benchResponseWriter struct {
+	h http.Header
+}
+
+func (w *benchResponseWriter) Header() http.Header         { return w.h }
+func (w *benchResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *benchResponseWriter) WriteHeader(int)             {}
+
+func benchRouter() *Router {
+	r := NewRouter(false)
+	h := func(ctx Context) error {
+		return nil
+	}
+
+	for i := 0; i < 50; i++ {
+		r.GET(fmt.Sprintf("/static/%d/path", i), h)
+		r.GET(fmt.Sprintf("/resources%d/{id}", i), h)
+		r.POST(fmt.Sprintf("/resources%d/{id}/items/{item}", i), h)
+	}
+
+	r.GET("/users/{id}/posts/{post}", func(ctx Context) error {
+		p := ctx.Params()
+		if p.String("id") == "" || p.String("post") == "" {
+			return ctx.NoContent()
+		}
+		return nil
+	})
+
+	r.GET("/orders/{id:int}", func(ctx Context) error {
+		if _, err := ctx.Params().Int("id"); err != nil {
+			return err
+		}
+		return nil
+	})
+
+	return r
+}
+
+func benchmarkRequest(b *testing.B, r *Router, method, path string) {
+	req := httptest.NewRequest(method, path, nil)
+	w := &benchResponseWriter{h: http.Header{}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(w, req)
+	}
+}
+
+func Benchmark_Router_Static(b *testing.B) {
+	benchmarkRequest(b, benchRouter(), "GET", "/static/49/path")
+}
+
+func Benchmark_Router_Param(b *testing.B) {
+	benchmarkRequest(b, benchRouter(), "GET", "/resources49/1")
+}
+
+func Benchmark_Router_Params(b *testing.B) {
+	benchmarkRequest(b, benchRouter(), "GET", "/users/1/posts/2")
+}
+
+func Benchmark_Router_Constraint(b *testing.B) {
+	benchmarkRequest(b, benchRouter(), "GET", "/orders/123")
+}
+
+func Benchmark_Router_Not_Found(b *testing.B) {
+	benchmarkRequest(b, benchRouter(), "GET", "/missing/path")
+}
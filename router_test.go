@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -261,3 +263,538 @@ func Test_Router_ServeFiles_Not_Found(t *testing.T) {
 	b, _ := ioutil.ReadAll(res.Body)
 	assert.Contains(t, string(b), "could not find /temp")
 }
+
+func Test_Router_URL(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/users/{id:[0-9]+}", func(ctx Context) error {
+		return ctx.NoContent()
+	}).Name("user")
+
+	g := r.Group("/api")
+
+	g.GET("/posts/{slug}", func(ctx Context) error {
+		return ctx.NoContent()
+	}).Name("post")
+
+	u, err := r.URL("user", "id", "1")
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, "/users/1", u)
+
+	u, err = r.URL("post", "slug", "hello")
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, "/api/posts/hello", u)
+
+	u, err = g.URL("user", "id", "2")
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, "/users/2", u)
+}
+
+func Test_Router_URL_Errors(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/users/{id:[0-9]+}", func(ctx Context) error {
+		return ctx.NoContent()
+	}).Name("user")
+
+	_, err := r.URL("unknown")
+	assert.Error(t, err, "should throw error")
+	assert.Contains(t, err.Error(), "could not find route with name 'unknown'")
+
+	_, err = r.URL("user")
+	assert.Error(t, err, "should throw error")
+	assert.Contains(t, err.Error(), "missing route variable")
+
+	_, err = r.URL("user", "id", "abc")
+	assert.Error(t, err, "should throw error")
+	assert.Contains(t, err.Error(), "doesn't match")
+
+	_, err = r.URL("user", "id")
+	assert.Error(t, err, "should throw error")
+}
+
+func Test_Route_Rename(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	route := r.GET("/asd", func(ctx Context) error {
+		return ctx.NoContent()
+	}).Name("first")
+
+	route.Name("second")
+	assert.Equal(t, "second", route.GetName())
+
+	_, err := r.URL("first")
+	assert.Error(t, err, "should throw error")
+
+	u, err := r.URL("second")
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, "/asd", u)
+}
+
+func Test_Router_Nested_Group(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/", func(ctx Context) error {
+		return ctx.String(200, "root")
+	})
+
+	api := r.Group("/api")
+
+	var v1 *Router
+	api.Group("/v1", func(g *Router) {
+		v1 = g
+
+		g.GET("/users", func(ctx Context) error {
+			return ctx.String(200, "users")
+		})
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/users", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "users", string(b))
+
+	assert.Len(t, r.routes, 2)
+	assert.Len(t, api.routes, 1)
+	assert.Len(t, v1.routes, 1)
+	assert.Equal(t, "/api/v1/users", r.routes[1].Path)
+}
+
+func Test_Router_Group_Inherits(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.SetCharset("iso-8859-1")
+	r.SetErrorHandlers(map[int]ErrorHandler{
+		400: func(code int, err error, ctx Context) error {
+			return ctx.String(code, "custom "+err.Error())
+		},
+	})
+
+	g := r.Group("/api").Group("/v1")
+
+	g.POST("/bind", func(ctx Context) error {
+		var body struct {
+			Msg string `json:"msg"`
+		}
+		if err := ctx.Bind(&body); err != nil {
+			return err
+		}
+		return ctx.String(200, body.Msg)
+	})
+
+	g.GET("/error", func(ctx Context) error {
+		return ctx.Error(400, errors.New("error"))
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/v1/bind", ts.URL), strings.NewReader(`{"msg":"hello"}`))
+	assert.NoError(t, err, "should not throw any error")
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, "hello", string(b))
+	assert.Equal(t, "text/plain; charset=iso-8859-1", res.Header.Get(HeaderContentType))
+
+	req, err = http.NewRequest("GET", fmt.Sprintf("%s/api/v1/error", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	b, _ = ioutil.ReadAll(res.Body)
+	assert.Equal(t, 400, res.StatusCode)
+	assert.Equal(t, "custom error", string(b))
+}
+
+func Test_Router_Auto_OPTIONS(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/asd", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	r.PUT("/asd", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	r.GET("/custom", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	r.OPTIONS("/custom", func(ctx Context) error {
+		ctx.Response().Header().Set(HeaderAllow, "GET")
+		return ctx.String(200, "custom")
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	req, err := http.NewRequest("OPTIONS", fmt.Sprintf("%s/asd", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, "GET, HEAD, OPTIONS, PUT", res.Header.Get(HeaderAllow))
+
+	req, err = http.NewRequest("OPTIONS", fmt.Sprintf("%s/custom", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "GET", res.Header.Get(HeaderAllow))
+	assert.Equal(t, "custom", string(b))
+
+	req, err = http.NewRequest("OPTIONS", fmt.Sprintf("%s/missing", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func Test_Router_Auto_HEAD(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	body := strings.Repeat("a", 10000)
+
+	r.GET("/users/{id}", func(ctx Context) error {
+		ctx.Response().Header().Set("X-Id", ctx.Params().String("id"))
+		return ctx.String(200, body)
+	})
+
+	r.GET("/custom", func(ctx Context) error {
+		return ctx.String(200, body)
+	})
+
+	r.HEAD("/custom", func(ctx Context) error {
+		ctx.Response().Header().Set("X-Custom", "true")
+		return ctx.NoContent()
+	})
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("HEAD", "/users/1", nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "1", res.Header().Get("X-Id"))
+	assert.Equal(t, "10000", res.Header().Get(HeaderContentLength))
+	assert.Equal(t, 0, res.Body.Len())
+
+	res = httptest.NewRecorder()
+	req = httptest.NewRequest("HEAD", "/custom", nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusNoContent, res.Code)
+	assert.Equal(t, "true", res.Header().Get("X-Custom"))
+}
+
+func Test_Router_Handle_Custom_Method(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.Handle("PROPFIND", "/dav", func(ctx Context) error {
+		return ctx.String(207, "PROPFIND")
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	req, err := http.NewRequest("PROPFIND", fmt.Sprintf("%s/dav", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, 207, res.StatusCode)
+	assert.Equal(t, "PROPFIND", string(b))
+
+	req, err = http.NewRequest("PURGE", fmt.Sprintf("%s/dav", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+	assert.Equal(t, "OPTIONS, PROPFIND", res.Header.Get(HeaderAllow))
+}
+
+func Test_Router_Match(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	routes := r.Match([]string{"GET", "REPORT"}, "/asd", func(ctx Context) error {
+		return ctx.String(200, ctx.Request().Method)
+	})
+	assert.Len(t, routes, 2)
+	assert.Len(t, r.Routes(), 2)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for _, m := range []string{"GET", "REPORT"} {
+		req, err := http.NewRequest(m, fmt.Sprintf("%s/asd", ts.URL), nil)
+		assert.NoError(t, err, "should not throw any error")
+		res, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err, "should not throw any error")
+		b, _ := ioutil.ReadAll(res.Body)
+		assert.Equal(t, m, string(b))
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/asd", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+	assert.Equal(t, "GET, HEAD, OPTIONS, REPORT", res.Header.Get(HeaderAllow))
+}
+
+func Test_Router_Any(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.Any("/asd", func(ctx Context) error {
+		return ctx.String(200, ctx.Request().Method)
+	})
+	assert.Len(t, r.Routes(), len(anyMethods))
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for _, m := range []string{"GET", "POST", "PUT", "PATCH", "DELETE"} {
+		req, err := http.NewRequest(m, fmt.Sprintf("%s/asd", ts.URL), nil)
+		assert.NoError(t, err, "should not throw any error")
+		res, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err, "should not throw any error")
+		b, _ := ioutil.ReadAll(res.Body)
+		assert.Equal(t, m, string(b))
+	}
+}
+
+func Test_Router_Host(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/", func(ctx Context) error {
+		return ctx.String(200, "root")
+	})
+
+	r.Host("Status.Example.com").GET("/", func(ctx Context) error {
+		return ctx.String(200, "status")
+	})
+
+	h := r.Host("{tenant}.example.com")
+	h.SetErrorHandlers(map[int]ErrorHandler{
+		404: func(code int, err error, ctx Context) error {
+			return ctx.String(code, "tenant not found")
+		},
+	})
+
+	h.Group("/api").GET("/users/{id}", func(ctx Context) error {
+		p := ctx.Params()
+		return ctx.String(200, p.String("tenant")+" "+p.String("id"))
+	})
+
+	table := []struct {
+		host string
+		path string
+		code int
+		body string
+	}{
+		{"acme.example.com", "/api/users/1", 200, "acme 1"},
+		{"acme.example.com:8080", "/api/users/2", 200, "acme 2"},
+		{"ACME.EXAMPLE.COM", "/api/users/3", 200, "acme 3"},
+		{"other.com", "/api/users/1", 404, "could not find /api/users/1"},
+		{"other.com", "/", 200, "root"},
+		{"acme.example.com", "/api/missing", 404, "tenant not found"},
+		{"status.example.com", "/", 200, "status"},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", tc.path, nil)
+		req.Host = tc.host
+		r.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.code, res.Code, tc.host+tc.path)
+		assert.Equal(t, tc.body, res.Body.String(), tc.host+tc.path)
+	}
+
+	routes := r.Routes()
+	assert.Equal(t, "{tenant}.example.com", routes[2].Host)
+	assert.Equal(t, "/api/users/{id}", routes[2].Path)
+}
+
+func Test_Router_Conflicts(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	var warnings []string
+	r.logf = func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	first := func(ctx Context) error {
+		return ctx.String(200, "first")
+	}
+	second := func(ctx Context) error {
+		return ctx.String(200, "second")
+	}
+
+	r.GET("/users/{id}", first)
+	r.POST("/users/{name}", second)
+	r.GET("/users/me", second)
+	r.GET("/users/{id:int}", second)
+	r.GET("/files/*path", first)
+	assert.Len(t, warnings, 0)
+
+	r.GET("/users/{id}", second)
+	r.GET("/users/{name}", second)
+	r.Group("/users").GET("/{n:-?[0-9]+}", first)
+	r.POST("/files/*name", second)
+	assert.Len(t, warnings, 4)
+	assert.Contains(t, warnings[0], "route GET /users/{id} (github.com/JacobSoderblom/otto.Test_Router_Conflicts.func3) is already registered by GET /users/{id} (github.com/JacobSoderblom/otto.Test_Router_Conflicts.func2)")
+	assert.Contains(t, warnings[1], "route GET /users/{name} (github.com/JacobSoderblom/otto.Test_Router_Conflicts.func3) overlaps with GET /users/{id}")
+	assert.Contains(t, warnings[2], "overlaps with GET /users/{id:int}")
+	assert.Contains(t, warnings[3], "overlaps with GET /files/*path")
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/users/abc", nil))
+	assert.Equal(t, "first", res.Body.String())
+
+	// a duplicate is not added and does not change the route that is served
+	n := len(r.Routes())
+	a := r.GET("/a", first).Name("a")
+	b := r.GET("/a", second).Name("b").Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			return ctx.String(500, "duplicate")
+		}
+	})
+	assert.False(t, a == b)
+	assert.Equal(t, "a", a.GetName())
+	assert.Len(t, r.Routes(), n+1)
+
+	u, err := r.URL("a")
+	assert.NoError(t, err)
+	assert.Equal(t, "/a", u)
+	_, err = r.URL("b")
+	assert.Error(t, err)
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/a", nil))
+	assert.Equal(t, "first", res.Body.String())
+	assert.True(t, r.Remove("GET", "/a"))
+	assert.Len(t, r.Routes(), n)
+
+	r.SetStrictRoutes(true)
+
+	assert.Panics(t, func() { r.GET("/users/{id}", second) })
+	assert.Panics(t, func() { r.Group("/api").GET("/../users/{user}", second) })
+	assert.NotPanics(t, func() { r.PUT("/users/{id}", second) })
+
+	defer func() {
+		err, ok := recover().(RouteConflict)
+		assert.True(t, ok)
+		assert.Equal(t, "/users/{id}", err.Existing.Path)
+	}()
+	r.DELETE("/users/{id}", first)
+	r.DELETE("/users/{user}", first)
+}
+
+func Test_Router_Remove(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+	api := r.Group("/api")
+
+	started, done := make(chan struct{}), make(chan struct{})
+	api.GET("/users/{id}", func(ctx Context) error {
+		close(started)
+		<-done
+		return ctx.String(200, "user")
+	}).Name("user")
+	api.POST("/users/{id}", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	inflight := httptest.NewRecorder()
+	go func() {
+		<-started
+		assert.True(t, api.Remove("GET", "/users/{id}"))
+		close(done)
+	}()
+	r.ServeHTTP(inflight, httptest.NewRequest("GET", "/api/users/1", nil))
+	assert.Equal(t, "user", inflight.Body.String())
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/api/users/1", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+
+	assert.False(t, r.Remove("GET", "/api/users/{id}"))
+	assert.Len(t, r.Routes(), 1)
+	assert.Len(t, api.Routes(), 1)
+
+	_, err := r.URL("user", "id", "1")
+	assert.Error(t, err, "should throw error")
+
+	assert.True(t, r.Remove("POST", "/api/users/{id}"))
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/api/users/1", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
+
+func Test_Router_Live_Changes(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	h := func(ctx Context) error {
+		return ctx.NoContent()
+	}
+	home := r.GET("/", h)
+	noop := func(next HandlerFunc) HandlerFunc {
+		return next
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			home.Use(noop).Deprecate(Deprecation{})
+			r.Use(noop)
+
+			p := fmt.Sprintf("/items/%d", i)
+			r.GET(p, h).Name(p)
+			r.Group("/groups").GET(p, h)
+			if i%2 == 0 {
+				r.Remove("GET", p)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, httptest.NewRequest("GET", fmt.Sprintf("/items/%d", i), nil))
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+			r.URL(fmt.Sprintf("/items/%d", i))
+			r.Routes()
+		}
+	}()
+	wg.Wait()
+
+	for i, code := range []int{http.StatusNoContent, http.StatusNotFound} {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest("GET", fmt.Sprintf("/items/%d", i+1), nil))
+		assert.Equal(t, code, res.Code)
+	}
+}
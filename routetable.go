@@ -0,0 +1,170 @@
+package otto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RouteInfo describes a registered route
+type RouteInfo struct {
+	Method     string     `json:"method"`
+	Host       string     `json:"host,omitempty"`
+	Path       string     `json:"path"`
+	Name       string     `json:"name,omitempty"`
+	Middleware int        `json:"middleware"`
+	Handler    string     `json:"handler"`
+	Redirect   string     `json:"redirect,omitempty"`
+	Matchers   []string   `json:"matchers,omitempty"`
+	Deprecated bool       `json:"deprecated,omitempty"`
+	Sunset     *time.Time `json:"sunset,omitempty"`
+	Successor  string     `json:"successor,omitempty"`
+}
+
+// RouteTable is a list of RouteInfo sorted by path and method
+type RouteTable []RouteInfo
+
+// Routes returns information about all routes registered on the Router
+// and the groups created from it
+func (r *Router) Routes() RouteTable {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t := make(RouteTable, 0, len(r.routes))
+	for _, route := range r.routes {
+		t = append(t, route.info())
+	}
+	return t
+}
+
+func (r *Route) info() RouteInfo {
+	ri := RouteInfo{
+		Method:     r.Method,
+		Host:       r.Host,
+		Path:       r.Path,
+		Name:       r.name,
+		Middleware: len(r.router.middleware) + len(r.middleware),
+		Handler:    funcName(r.HandlerFunc),
+	}
+
+	for _, m := range r.matchers {
+		ri.Matchers = append(ri.Matchers, m.String())
+	}
+
+	if d := r.deprecation; d != nil {
+		ri.Deprecated = true
+		ri.Successor = d.Link
+		if !d.Sunset.IsZero() {
+			sunset := d.Sunset
+			ri.Sunset = &sunset
+		}
+	}
+
+	if r.redirect != nil {
+		ri.Handler = fmt.Sprintf("redirect %d %s", r.redirect.code, r.redirect.to)
+		ri.Redirect = r.redirect.to
+	}
+
+	return ri
+}
+
+// JSON writes the route table as json to w
+func (t RouteTable) JSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return errors.Wrap(enc.Encode(t), "failed to encode route table to json")
+}
+
+// Text writes the route table as an aligned text table to w
+func (t RouteTable) Text(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tHOST\tPATH\tNAME\tMIDDLEWARE\tHANDLER")
+	for _, ri := range t {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n", ri.Method, ri.Host, ri.Path, ri.Name, ri.Middleware, ri.Handler)
+	}
+	return errors.Wrap(tw.Flush(), "failed to write route table")
+}
+
+// DOT writes the route table as a Graphviz graph to w. Every path
+// segment becomes a node and every route is a leaf of its path,
+// routes on a host or with Matchers get a leaf of their own
+func (t RouteTable) DOT(w io.Writer) error {
+	b := &bytes.Buffer{}
+	b.WriteString("digraph routes {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	b.WriteString("\t\"/\";\n")
+
+	nodes := map[string]bool{"/": true}
+	edges := map[string]bool{}
+
+	edge := func(from, to string) {
+		e := fmt.Sprintf("\t%q -> %q;\n", from, to)
+		if !edges[e] {
+			edges[e] = true
+			b.WriteString(e)
+		}
+	}
+
+	for _, ri := range t {
+		parent := "/"
+		for _, seg := range strings.Split(strings.Trim(ri.Path, "/"), "/") {
+			if seg == "" {
+				continue
+			}
+			node := strings.TrimSuffix(parent, "/") + "/" + seg
+			if !nodes[node] {
+				nodes[node] = true
+				fmt.Fprintf(b, "\t%q [label=%q];\n", node, seg)
+			}
+			edge(parent, node)
+			parent = node
+		}
+
+		// routes with the same method and path can differ
+		// by host and Matchers, which makes them separate leaves
+		leaf := ri.Method + " " + ri.Host + ri.Path
+		label := ri.Method
+		if ri.Host != "" {
+			label += " " + ri.Host
+		}
+		if len(ri.Matchers) > 0 {
+			matchers := strings.Join(ri.Matchers, ", ")
+			leaf += " [" + matchers + "]"
+			label += "\\n" + matchers
+		}
+		label += "\\n" + ri.Handler
+		if ri.Name != "" {
+			label += "\\n(" + ri.Name + ")"
+		}
+		if ri.Deprecated {
+			label += "\\ndeprecated"
+		}
+		fmt.Fprintf(b, "\t%q [shape=ellipse, label=\"%s\"];\n", leaf, strings.Replace(label, "\"", "\\\"", -1))
+		edge(parent, leaf)
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return errors.Wrap(err, "failed to write route graph")
+}
+
+func funcName(f interface{}) string {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	}
+	if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
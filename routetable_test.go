@@ -0,0 +1,101 @@
+package otto
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func listUsers(ctx Context) error {
+	return ctx.NoContent()
+}
+
+func routeTableRouter() *Router {
+	r := NewRouter(false)
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return next
+	})
+
+	r.GET("/", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	g := r.Group("/api")
+	g.GET("/users", listUsers).Name("users")
+	g.POST("/users", listUsers, func(next HandlerFunc) HandlerFunc {
+		return next
+	})
+
+	return r
+}
+
+func Test_Router_Routes(t *testing.T) {
+	t.Parallel()
+	r := routeTableRouter()
+
+	routes := r.Routes()
+	assert.Len(t, routes, 3)
+
+	assert.Equal(t, "GET", routes[0].Method)
+	assert.Equal(t, "/", routes[0].Path)
+
+	assert.Equal(t, "GET", routes[1].Method)
+	assert.Equal(t, "/api/users", routes[1].Path)
+	assert.Equal(t, "users", routes[1].Name)
+	assert.Equal(t, 1, routes[1].Middleware)
+	assert.Equal(t, "github.com/JacobSoderblom/otto.listUsers", routes[1].Handler)
+
+	assert.Equal(t, "POST", routes[2].Method)
+	assert.Equal(t, 2, routes[2].Middleware)
+}
+
+func Test_RouteTable_JSON(t *testing.T) {
+	t.Parallel()
+	r := routeTableRouter()
+
+	var b bytes.Buffer
+	assert.NoError(t, r.Routes().JSON(&b), "should not throw any error")
+
+	var routes []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b.Bytes(), &routes), "should not throw any error")
+	assert.Len(t, routes, 3)
+	assert.Equal(t, "/api/users", routes[1]["path"])
+	assert.Equal(t, "users", routes[1]["name"])
+}
+
+func Test_RouteTable_Text(t *testing.T) {
+	t.Parallel()
+	r := routeTableRouter()
+
+	var b bytes.Buffer
+	assert.NoError(t, r.Routes().Text(&b), "should not throw any error")
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.True(t, strings.HasPrefix(lines[0], "METHOD"))
+	assert.Contains(t, lines[2], "/api/users")
+	assert.Contains(t, lines[2], "otto.listUsers")
+	assert.Equal(t, strings.Index(lines[0], "PATH"), strings.Index(lines[2], "/api/users"))
+}
+
+func Test_RouteTable_DOT(t *testing.T) {
+	t.Parallel()
+	r := routeTableRouter()
+	r.Host("admin.example.com").GET("/api/users", listUsers)
+	r.When(Header("X-Version", "2")).GET("/api/users", listUsers)
+
+	var b bytes.Buffer
+	assert.NoError(t, r.Routes().DOT(&b), "should not throw any error")
+
+	dot := b.String()
+	assert.True(t, strings.HasPrefix(dot, "digraph routes {"))
+	assert.Contains(t, dot, `"/" -> "/api";`)
+	assert.Contains(t, dot, `"/api" -> "/api/users";`)
+	assert.Contains(t, dot, `"/api/users" -> "GET /api/users";`)
+	assert.Contains(t, dot, `"/api/users" -> "GET admin.example.com/api/users";`)
+	assert.Contains(t, dot, `"/api/users" -> "GET /api/users [header X-Version=2]";`)
+	assert.Contains(t, dot, `"/" -> "GET /";`)
+}
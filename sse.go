@@ -0,0 +1,241 @@
+package otto
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Event is a Server-Sent Event. Data can have multiple lines,
+// Retry tells the client how long to wait before it reconnects
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+	Retry time.Duration
+}
+
+// EventStream sends Server-Sent Events to the client
+type EventStream struct {
+	c         *context
+	w         *streamWriter
+	heartbeat time.Duration
+}
+
+// SSE starts a stream of Server-Sent Events, the headers of the
+// response are written so it should only be called once
+func (c *context) SSE() *EventStream {
+	h := c.res.Header()
+	h.Set(HeaderContentType, MIMETextEventStream)
+	h.Set(HeaderCacheControl, "no-cache")
+	h.Set(HeaderXAccelBuffering, "no")
+
+	c.res.WriteHeader(200)
+	c.res.Flush()
+
+	return &EventStream{
+		c: c,
+		w: &streamWriter{c: c},
+	}
+}
+
+// LastEventID returns the ID of the last event the client
+// got before it reconnected
+func (s *EventStream) LastEventID() string {
+	return s.c.req.Header.Get(HeaderLastEventID)
+}
+
+// SetHeartbeat sets how often Serve sends a comment to keep
+// the connection open when there are no events
+func (s *EventStream) SetHeartbeat(d time.Duration) {
+	s.heartbeat = d
+}
+
+// Send sends the event to the client
+func (s *EventStream) Send(e Event) error {
+	buf := new(bytes.Buffer)
+
+	if e.ID != "" {
+		writeField(buf, "id", oneLine(e.ID))
+	}
+	if e.Event != "" {
+		writeField(buf, "event", oneLine(e.Event))
+	}
+	if e.Retry > 0 {
+		writeField(buf, "retry", strconv.FormatInt(int64(e.Retry/time.Millisecond), 10))
+	}
+	if e.Data != "" || e.Event != "" {
+		for _, line := range splitLines(e.Data) {
+			writeField(buf, "data", line)
+		}
+	}
+	buf.WriteByte('\n')
+
+	_, err := s.w.Write(buf.Bytes())
+	return errors.Wrap(err, "failed to send event")
+}
+
+// Comment sends a comment, which is ignored by the client
+func (s *EventStream) Comment(text string) error {
+	buf := new(bytes.Buffer)
+	for _, line := range splitLines(text) {
+		writeField(buf, "", line)
+	}
+	buf.WriteByte('\n')
+
+	_, err := s.w.Write(buf.Bytes())
+	return errors.Wrap(err, "failed to send comment")
+}
+
+// Serve sends the events to the client until the channel is closed or the
+// client disconnects, a heartbeat comment is sent if it is set. Errors are
+// logged, since the response is already sent
+func (s *EventStream) Serve(events <-chan Event) error {
+	var tick <-chan time.Time
+	if s.heartbeat > 0 {
+		t := time.NewTicker(s.heartbeat)
+		defer t.Stop()
+		tick = t.C
+	}
+
+	var err error
+
+	for err == nil {
+		select {
+		case <-s.c.Context().Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			err = s.Send(e)
+		case <-tick:
+			err = s.Comment("heartbeat")
+		}
+	}
+
+	s.c.dropError(err)
+	return nil
+}
+
+func writeField(buf *bytes.Buffer, name, val string) {
+	buf.WriteString(name)
+	buf.WriteString(": ")
+	buf.WriteString(val)
+	buf.WriteByte('\n')
+}
+
+// splitLines splits the text on all line endings that are
+// allowed in an event stream, a line ending would end the field
+func splitLines(s string) []string {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	s = strings.Replace(s, "\r", "\n", -1)
+	return strings.Split(s, "\n")
+}
+
+// oneLine removes the line endings of a field that can not have multiple lines
+func oneLine(s string) string {
+	return strings.Join(splitLines(s), "")
+}
+
+// Hub fans out events to all subscribers, like all clients that watch a
+// dashboard. Subscribers that do not keep up are disconnected, so that
+// they can reconnect and continue from the last event they got
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	buffer int
+	closed bool
+}
+
+// NewHub creates a Hub where each subscriber can have
+// buffer events waiting before it is disconnected
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		subs:   map[*Subscription]struct{}{},
+		buffer: buffer,
+	}
+}
+
+// Subscription receives the events that are published to a Hub
+type Subscription struct {
+	hub    *Hub
+	events chan Event
+}
+
+// Subscribe adds a subscriber to the hub, the subscription
+// must be closed when the subscriber is gone
+func (h *Hub) Subscribe() *Subscription {
+	s := &Subscription{
+		hub:    h,
+		events: make(chan Event, h.buffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(s.events)
+		return s
+	}
+
+	h.subs[s] = struct{}{}
+	return s
+}
+
+// Publish sends the event to all subscribers
+func (h *Hub) Publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subs {
+		select {
+		case s.events <- e:
+		default:
+			h.remove(s)
+		}
+	}
+}
+
+// Subscribers returns the number of subscribers
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Close closes all subscriptions, subscribing to
+// a closed Hub returns a closed subscription
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subs {
+		h.remove(s)
+	}
+	h.closed = true
+}
+
+func (h *Hub) remove(s *Subscription) {
+	if _, ok := h.subs[s]; ok {
+		delete(h.subs, s)
+		close(s.events)
+	}
+}
+
+// Events returns the channel of events, which is
+// closed when the subscription is closed
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close removes the subscription from the Hub
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
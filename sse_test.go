@@ -0,0 +1,141 @@
+package otto
+
+import (
+	"bufio"
+	gocontext "context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Context_SSE(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/events", func(ctx Context) error {
+		s := ctx.SSE()
+		assert.Equal(t, "41", s.LastEventID())
+
+		if err := s.Comment("hello\nworld"); err != nil {
+			return err
+		}
+		if err := s.Send(Event{ID: "42", Event: "update", Data: "a\nb\r\nc", Retry: 3 * time.Second}); err != nil {
+			return err
+		}
+		return s.Send(Event{ID: "4\n3", Data: "d"})
+	})
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/events", nil)
+	req.Header.Set(HeaderLastEventID, "41")
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, MIMETextEventStream, res.Header().Get(HeaderContentType))
+	assert.Equal(t, "no-cache", res.Header().Get(HeaderCacheControl))
+	assert.True(t, res.Flushed)
+	assert.Equal(t, ": hello\n: world\n\n"+
+		"id: 42\nevent: update\nretry: 3000\ndata: a\ndata: b\ndata: c\n\n"+
+		"id: 43\ndata: d\n\n", res.Body.String())
+}
+
+func Test_Hub(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	hub := NewHub(8)
+	defer hub.Close()
+
+	r.GET("/events", func(ctx Context) error {
+		s := ctx.SSE()
+		s.SetHeartbeat(10 * time.Millisecond)
+
+		sub := hub.Subscribe()
+		defer sub.Close()
+
+		return s.Serve(sub.Events())
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	c, cancel := gocontext.WithCancel(gocontext.Background())
+	defer cancel()
+
+	req, err := http.NewRequest("GET", ts.URL+"/events", nil)
+	assert.NoError(t, err, "should not throw any error")
+	res, err := http.DefaultClient.Do(req.WithContext(c))
+	if !assert.NoError(t, err, "should not throw any error") {
+		return
+	}
+	defer res.Body.Close()
+
+	br := bufio.NewReader(res.Body)
+	readEvent := func() string {
+		var lines []string
+		for {
+			line, err := br.ReadString('\n')
+			if err != nil || line == "\n" {
+				return strings.Join(lines, "")
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	assert.Equal(t, ": heartbeat\n", readEvent())
+	assert.Equal(t, 1, hub.Subscribers())
+
+	hub.Publish(Event{Event: "update", Data: "1"})
+
+	event := readEvent()
+	for event == ": heartbeat\n" {
+		event = readEvent()
+	}
+	assert.Equal(t, "event: update\ndata: 1\n", event)
+
+	cancel()
+
+	// the subscription is closed when the client is gone
+	for i := 0; hub.Subscribers() > 0 && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 0, hub.Subscribers())
+}
+
+func Test_Hub_Disconnect(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(1)
+
+	slow := hub.Subscribe()
+	fast := hub.Subscribe()
+	assert.Equal(t, 2, hub.Subscribers())
+
+	hub.Publish(Event{Data: "1"})
+	assert.Equal(t, "1", (<-fast.Events()).Data)
+
+	// the slow subscriber is disconnected when its buffer is full
+	hub.Publish(Event{Data: "2"})
+	assert.Equal(t, 1, hub.Subscribers())
+	assert.Equal(t, "2", (<-fast.Events()).Data)
+
+	e, ok := <-slow.Events()
+	assert.True(t, ok)
+	assert.Equal(t, "1", e.Data)
+	_, ok = <-slow.Events()
+	assert.False(t, ok)
+	slow.Close()
+
+	hub.Close()
+	_, ok = <-fast.Events()
+	assert.False(t, ok)
+	fast.Close()
+
+	_, ok = <-hub.Subscribe().Events()
+	assert.False(t, ok)
+	assert.Equal(t, 0, hub.Subscribers())
+}
@@ -0,0 +1,69 @@
+package otto
+
+import (
+	"io"
+	"log"
+
+	"github.com/pkg/errors"
+)
+
+// Stream copies r to the response as it is read, see StreamFunc
+func (c *context) Stream(code int, ct string, r io.Reader) error {
+	return c.StreamFunc(code, ct, func(w io.Writer) error {
+		_, err := io.Copy(w, r)
+		return err
+	})
+}
+
+// StreamFunc writes the response as fn produces it and flushes it to the
+// client after each write, writing stops when the request is cancelled. The
+// content type is used as it is, without adding the charset. An error that
+// happens while streaming is logged, since the response is already sent
+func (c *context) StreamFunc(code int, ct string, fn func(w io.Writer) error) error {
+	if ct != "" {
+		c.res.Header().Set(HeaderContentType, ct)
+	}
+	c.res.WriteHeader(code)
+
+	if err := fn(&streamWriter{c: c}); err != nil {
+		c.dropError(errors.Wrap(err, "failed to stream response"))
+	}
+
+	return nil
+}
+
+// dropError logs an error that happens after the response is sent, the
+// error can not be rendered anymore. There is no one to tell when the
+// client is gone, so the error is not logged then
+func (c *context) dropError(err error) {
+	if c.Context().Err() != nil {
+		return
+	}
+
+	logf := log.Printf
+	if c.router != nil {
+		logf = c.router.logf
+	}
+	logf("otto: %s %s: %v", c.req.Method, c.req.URL.Path, err)
+}
+
+// streamWriter flushes every write to the client and
+// stops writing when the request is cancelled
+type streamWriter struct {
+	c *context
+}
+
+func (w *streamWriter) Write(b []byte) (int, error) {
+	if err := w.c.Context().Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := w.c.res.Write(b)
+	if err != nil {
+		return n, err
+	}
+
+	w.c.res.Flush()
+
+	return n, nil
+}
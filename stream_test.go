@@ -0,0 +1,113 @@
+package otto
+
+import (
+	"bufio"
+	gocontext "context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Context_Stream(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/", func(ctx Context) error {
+		return ctx.Stream(200, "text/csv", strings.NewReader("a,b\n1,2\n"))
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "text/csv", res.Header().Get(HeaderContentType))
+	assert.Equal(t, "a,b\n1,2\n", res.Body.String())
+	assert.True(t, res.Flushed)
+}
+
+func Test_Context_StreamFunc_Error(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	var logs []string
+	r.logf = func(format string, args ...interface{}) {
+		logs = append(logs, fmt.Sprintf(format, args...))
+	}
+
+	r.GET("/", func(ctx Context) error {
+		return ctx.StreamFunc(200, "text/csv", func(w io.Writer) error {
+			io.WriteString(w, "a,b\n")
+			return errors.New("db failed")
+		})
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "a,b\n", res.Body.String())
+	assert.Equal(t, []string{"otto: GET /: failed to stream response: db failed"}, logs)
+}
+
+func Test_Context_StreamFunc(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	next := make(chan struct{})
+	done := make(chan error, 1)
+
+	r.GET("/", func(ctx Context) error {
+		err := ctx.StreamFunc(200, "text/plain", func(w io.Writer) error {
+			for i := 0; ; i++ {
+				if _, err := fmt.Fprintf(w, "line %d\n", i); err != nil {
+					return err
+				}
+				select {
+				case <-next:
+				case <-time.After(time.Second):
+					t.Error("the client should get each line before the next is written")
+					return nil
+				}
+			}
+		})
+		done <- err
+		return err
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	c, cancel := gocontext.WithCancel(gocontext.Background())
+	req, err := http.NewRequest("GET", ts.URL, nil)
+	assert.NoError(t, err, "should not throw any error")
+
+	res, err := http.DefaultClient.Do(req.WithContext(c))
+	if !assert.NoError(t, err, "should not throw any error") {
+		cancel()
+		return
+	}
+	defer res.Body.Close()
+
+	br := bufio.NewReader(res.Body)
+	for i := 0; i < 3; i++ {
+		line, err := br.ReadString('\n')
+		assert.NoError(t, err, "should not throw any error")
+		assert.Equal(t, fmt.Sprintf("line %d\n", i), line)
+		next <- struct{}{}
+	}
+
+	cancel()
+	close(next)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err, "should stop without error when the client is gone")
+	case <-time.After(time.Second):
+		t.Error("streaming should stop when the client is gone")
+	}
+}
@@ -0,0 +1,297 @@
+package otto
+
+import (
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Directories of the templates that are shared by all pages
+const (
+	LayoutsDir  = "layouts"
+	PartialsDir = "partials"
+)
+
+// contentTemplate is the template a layout renders the page with
+const contentTemplate = "content"
+
+// TemplateOptions configures Templates
+type TemplateOptions struct {
+	// Layout is the name of the layout that pages are rendered in,
+	// like "layouts/application". Pages are rendered without a layout
+	// if it is empty
+	Layout string
+	// Ext is the extension of the template files, ".html" by default
+	Ext string
+	// Funcs are added to the helper funcs of the templates
+	Funcs template.FuncMap
+	// Reload checks the templates for changes each time a template
+	// is rendered and loads them again if they have changed
+	Reload bool
+}
+
+// Templates is a Renderer that renders html/template templates from a
+// http.FileSystem, like http.Dir("templates").
+//
+// A template is named by its path without the extension, like "users/show".
+// Templates in the layouts and partials directories are shared by all pages,
+// a partial is used like {{ template "partials/nav" . }}. A layout renders
+// the page with {{ template "content" . }}, a page can define the content
+// and other blocks of the layout itself, otherwise the whole page is the
+// content.
+//
+// Besides the Funcs of the options the templates can use
+// {{ url "name" "param" value }} to build URLs to named routes
+// of the Router that renders the template
+type Templates struct {
+	fs        http.FileSystem
+	opts      TemplateOptions
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+	sum       uint64
+}
+
+// NewTemplates loads the templates from the file system
+func NewTemplates(fs http.FileSystem, opts TemplateOptions) (*Templates, error) {
+	if opts.Ext == "" {
+		opts.Ext = ".html"
+	}
+
+	t := &Templates{
+		fs:   fs,
+		opts: opts,
+	}
+
+	if err := t.load(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Render renders the page with the name in the layout of the options
+func (t *Templates) Render(w io.Writer, name string, data interface{}, ctx Context) error {
+	if t.opts.Reload {
+		if err := t.reload(); err != nil {
+			return err
+		}
+	}
+
+	t.mu.RLock()
+	tmpl, ok := t.templates[name]
+	t.mu.RUnlock()
+
+	if !ok {
+		return errors.Errorf("template %s does not exist", name)
+	}
+
+	// the loaded templates are never executed so that they can be
+	// cloned, the clone builds URLs with the Context it renders
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return errors.Wrapf(err, "failed to clone template %s", name)
+	}
+	tmpl.Funcs(template.FuncMap{"url": urlFunc(ctx)})
+
+	if t.opts.Layout != "" {
+		return tmpl.ExecuteTemplate(w, t.opts.Layout, data)
+	}
+
+	return tmpl.ExecuteTemplate(w, name, data)
+}
+
+func (t *Templates) funcs() template.FuncMap {
+	funcs := template.FuncMap{
+		"url": urlFunc(nil),
+	}
+	for k, fn := range t.opts.Funcs {
+		funcs[k] = fn
+	}
+	return funcs
+}
+
+// urlFunc returns the url helper that builds URLs with the Context
+func urlFunc(ctx Context) func(name string, params ...interface{}) (string, error) {
+	return func(name string, params ...interface{}) (string, error) {
+		if ctx == nil {
+			return "", errors.New("template is not rendered with a context")
+		}
+
+		pairs := make([]string, len(params))
+		for i, p := range params {
+			pairs[i] = fmt.Sprint(p)
+		}
+
+		return ctx.URL(name, pairs...)
+	}
+}
+
+// load parses all templates, every page gets its own set of templates
+// with the shared layouts and partials, so that pages can define the
+// same blocks
+func (t *Templates) load() error {
+	shared := template.New("").Funcs(t.funcs())
+	pages := map[string]string{}
+
+	h := fnv.New64a()
+
+	err := walk(t.fs, "/", func(p string, fi os.FileInfo) error {
+		if path.Ext(p) != t.opts.Ext {
+			return nil
+		}
+
+		stamp(h, p, fi)
+
+		src, err := readFile(t.fs, p)
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(p, "/"), t.opts.Ext)
+
+		if !isShared(name) {
+			pages[name] = src
+			return nil
+		}
+
+		if _, err := shared.New(name).Parse(src); err != nil {
+			return errors.Wrapf(err, "failed to parse template %s", name)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if t.opts.Layout != "" && shared.Lookup(t.opts.Layout) == nil {
+		return errors.Errorf("layout %s does not exist", t.opts.Layout)
+	}
+
+	templates := make(map[string]*template.Template, len(pages))
+
+	for name, src := range pages {
+		tmpl, err := shared.Clone()
+		if err != nil {
+			return errors.Wrapf(err, "failed to clone templates for %s", name)
+		}
+
+		content := tmpl.Lookup(contentTemplate)
+
+		if _, err := tmpl.New(name).Parse(src); err != nil {
+			return errors.Wrapf(err, "failed to parse template %s", name)
+		}
+
+		// the whole page is the content if it does not define it
+		if c := tmpl.Lookup(contentTemplate); c == nil || content != nil && c.Tree == content.Tree {
+			if _, err := tmpl.New(contentTemplate).Parse(fmt.Sprintf("{{ template %q . }}", name)); err != nil {
+				return errors.Wrapf(err, "failed to parse template %s", name)
+			}
+		}
+
+		templates[name] = tmpl
+	}
+
+	t.mu.Lock()
+	t.templates = templates
+	t.sum = h.Sum64()
+	t.mu.Unlock()
+
+	return nil
+}
+
+// reload loads the templates again if any of the files have changed
+func (t *Templates) reload() error {
+	h := fnv.New64a()
+
+	err := walk(t.fs, "/", func(p string, fi os.FileInfo) error {
+		if path.Ext(p) == t.opts.Ext {
+			stamp(h, p, fi)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	t.mu.RLock()
+	changed := t.sum != h.Sum64()
+	t.mu.RUnlock()
+
+	if !changed {
+		return nil
+	}
+
+	return t.load()
+}
+
+// stamp adds the file to the checksum of the templates
+func stamp(w io.Writer, p string, fi os.FileInfo) {
+	fmt.Fprintf(w, "%s %d %d\n", p, fi.Size(), fi.ModTime().UnixNano())
+}
+
+func isShared(name string) bool {
+	return strings.HasPrefix(name, LayoutsDir+"/") || strings.HasPrefix(name, PartialsDir+"/")
+}
+
+// walk calls fn for all files in the directory and its
+// subdirectories, in lexical order
+func walk(fs http.FileSystem, dir string, fn func(string, os.FileInfo) error) error {
+	f, err := fs.Open(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", dir)
+	}
+
+	fis, err := f.Readdir(-1)
+	f.Close()
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", dir)
+	}
+
+	sort.Slice(fis, func(i, j int) bool {
+		return fis[i].Name() < fis[j].Name()
+	})
+
+	for _, fi := range fis {
+		p := path.Join(dir, fi.Name())
+
+		if fi.IsDir() {
+			err = walk(fs, p, fn)
+		} else {
+			err = fn(p, fi)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readFile(fs http.FileSystem, p string) (string, error) {
+	f, err := fs.Open(p)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open %s", p)
+	}
+	defer f.Close()
+
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read %s", p)
+	}
+
+	return string(b), nil
+}
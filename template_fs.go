@@ -0,0 +1,14 @@
+//go:build go1.16
+// +build go1.16
+
+package otto
+
+import (
+	"io/fs"
+	"net/http"
+)
+
+// NewTemplatesFS loads the templates from a fs.FS, like an embed.FS
+func NewTemplatesFS(fsys fs.FS, opts TemplateOptions) (*Templates, error) {
+	return NewTemplates(http.FS(fsys), opts)
+}
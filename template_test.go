@@ -0,0 +1,160 @@
+package otto
+
+import (
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Templates_Render(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	tmpl, err := NewTemplates(http.Dir("testdata/templates"), TemplateOptions{
+		Layout: "layouts/application",
+		Funcs: template.FuncMap{
+			"upper": strings.ToUpper,
+		},
+	})
+	if !assert.NoError(t, err, "should not throw any error") {
+		return
+	}
+
+	r.SetRenderer(tmpl)
+
+	r.GET("/", func(ctx Context) error {
+		return ctx.Render(200, "home", "<otto>")
+	})
+
+	r.GET("/users/{id}", func(ctx Context) error {
+		return ctx.Render(200, "users/show", struct {
+			ID   int
+			Name string
+		}{1, "otto"})
+	}).Name("user")
+
+	r.GET("/missing", func(ctx Context) error {
+		return ctx.Render(200, "missing", nil)
+	})
+
+	table := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/", 200, "<title>otto</title>\n<nav>HOME</nav>\n\n<main><p>Hello &lt;otto&gt;</p>\n</main>\n"},
+		{"/users/1", 200, "<title>otto</title>\n<nav>HOME</nav>\n\n<main><a href=\"/users/1\">otto</a></main>\n"},
+		{"/missing", 500, "template missing does not exist"},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest("GET", tc.path, nil))
+		assert.Equal(t, tc.code, res.Code, tc.path)
+		assert.Contains(t, res.Body.String(), tc.body, tc.path)
+	}
+
+	// the templates build URLs with the Router that renders them
+	other := NewRouter(false)
+	other.SetRenderer(tmpl)
+	other.GET("/people/{id}", func(ctx Context) error {
+		return ctx.Render(200, "users/show", struct {
+			ID   int
+			Name string
+		}{2, "bot"})
+	}).Name("user")
+
+	res := httptest.NewRecorder()
+	other.ServeHTTP(res, httptest.NewRequest("GET", "/people/2", nil))
+	assert.Contains(t, res.Body.String(), "<a href=\"/people/2\">bot</a>")
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/users/1", nil))
+	assert.Contains(t, res.Body.String(), "<a href=\"/users/1\">otto</a>")
+}
+
+func Test_Templates_Without_Layout(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/", func(ctx Context) error {
+		return ctx.Render(200, "home", "otto")
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, 500, res.Code)
+	assert.Contains(t, res.Body.String(), "no renderer is set")
+
+	tmpl, err := NewTemplates(http.Dir("testdata/templates"), TemplateOptions{
+		Funcs: template.FuncMap{
+			"upper": strings.ToUpper,
+		},
+	})
+	if !assert.NoError(t, err, "should not throw any error") {
+		return
+	}
+	r.SetRenderer(tmpl)
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "text/html; charset=utf-8", res.Header().Get(HeaderContentType))
+	assert.Equal(t, "<p>Hello otto</p>\n", res.Body.String())
+
+	_, err = NewTemplates(http.Dir("testdata/templates"), TemplateOptions{Layout: "layouts/missing"})
+	assert.Error(t, err, "should throw error")
+}
+
+func Test_Templates_Reload(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "otto")
+	if !assert.NoError(t, err, "should not throw any error") {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, src string) {
+		os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755)
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644))
+	}
+
+	write("layouts/main.html", "<main>{{ template \"content\" . }}</main>")
+	write("home.html", "v1")
+
+	tmpl, err := NewTemplates(http.Dir(dir), TemplateOptions{
+		Layout: "layouts/main",
+		Reload: true,
+	})
+	if !assert.NoError(t, err, "should not throw any error") {
+		return
+	}
+
+	r := NewRouter(false)
+	r.SetRenderer(tmpl)
+	r.GET("/{page}", func(ctx Context) error {
+		return ctx.Render(200, ctx.Params().String("page"), nil)
+	})
+
+	render := func(p string) string {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest("GET", p, nil))
+		return res.Body.String()
+	}
+
+	assert.Equal(t, "<main>v1</main>", render("/home"))
+
+	write("home.html", "version 2")
+	write("about.html", "about")
+
+	assert.Equal(t, "<main>version 2</main>", render("/home"))
+	assert.Equal(t, "<main>about</main>", render("/about"))
+}
@@ -0,0 +1,944 @@
+package otto
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+type segmentKind uint8
+
+const (
+	staticSegment segmentKind = iota
+	paramSegment
+	patternSegment
+	catchAllSegment
+)
+
+// segment is a parsed part of a route path between two slashes
+type segment struct {
+	kind  segmentKind
+	tpl   string
+	name  string
+	re    *regexp.Regexp
+	names []string
+}
+
+// parseTemplate splits the path template into segments,
+// slashes inside of params are not treated as separators
+func parseTemplate(tpl string) ([]segment, error) {
+	if !strings.HasPrefix(tpl, "/") {
+		return nil, errors.Errorf("path '%s' must start with '/'", tpl)
+	}
+
+	var segs []segment
+	seen := map[string]bool{}
+	level, start := 0, 1
+
+	for i := 1; i <= len(tpl); i++ {
+		if i < len(tpl) {
+			switch tpl[i] {
+			case '{':
+				level++
+				continue
+			case '}':
+				level--
+				if level < 0 {
+					return nil, errors.Errorf("unbalanced braces in path '%s'", tpl)
+				}
+				continue
+			case '/':
+				if level > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		if level != 0 {
+			return nil, errors.Errorf("unbalanced braces in path '%s'", tpl)
+		}
+
+		seg, err := parseSegment(tpl[start:i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid path '%s'", tpl)
+		}
+
+		if seg.kind == catchAllSegment && i < len(tpl) {
+			return nil, errors.Errorf("catch-all '%s' must be the last segment in path '%s'", seg.tpl, tpl)
+		}
+
+		names := seg.names
+		if seg.kind == paramSegment || seg.kind == catchAllSegment {
+			names = []string{seg.name}
+		}
+		for _, name := range names {
+			if seen[name] {
+				return nil, errors.Errorf("duplicate param '%s' in path '%s'", name, tpl)
+			}
+			seen[name] = true
+		}
+
+		segs = append(segs, seg)
+		start = i + 1
+	}
+
+	return segs, nil
+}
+
+func parseSegment(s string) (segment, error) {
+	if strings.HasPrefix(s, "*") {
+		name := s[1:]
+		if name == "" || strings.ContainsAny(name, "{}*") {
+			return segment{}, errors.Errorf("invalid catch-all '%s'", s)
+		}
+		return segment{kind: catchAllSegment, tpl: s, name: name}, nil
+	}
+
+	if !strings.ContainsAny(s, "{}") {
+		return segment{kind: staticSegment, tpl: s}, nil
+	}
+
+	vars, err := templateVars(s)
+	if err != nil {
+		return segment{}, err
+	}
+
+	// a param only matches a single segment, a pattern that
+	// needs a slash to match would never match the path
+	for _, v := range vars {
+		if v.pattern != "" && needsSlash(v.pattern) {
+			return segment{}, errors.Errorf("pattern for param '%s' can only match with a '/', use a catch-all like '*%s' to match the rest of the path", v.name, v.name)
+		}
+	}
+
+	// a segment with a single param and nothing else
+	if len(vars) == 1 && vars[0].start == 0 && vars[0].end == len(s) {
+		seg := segment{kind: paramSegment, tpl: s, name: vars[0].name}
+		if vars[0].pattern != "" {
+			seg.re = regexp.MustCompile("^(?:" + vars[0].pattern + ")$")
+		}
+		return seg, nil
+	}
+
+	re, names := compileTemplate(s, vars, "[^/]+")
+	return segment{kind: patternSegment, tpl: s, re: re, names: names}, nil
+}
+
+// needsSlash reports if the pattern can only match a string with a slash
+func needsSlash(pattern string) bool {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	return !matchesWithoutSlash(re)
+}
+
+// matchesWithoutSlash reports if the regexp can match a string without a slash
+func matchesWithoutSlash(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return false
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if r == '/' {
+				return false
+			}
+		}
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			if re.Rune[i] != '/' || re.Rune[i+1] != '/' {
+				return true
+			}
+		}
+		return false
+	case syntax.OpStar, syntax.OpQuest:
+		return true
+	case syntax.OpRepeat:
+		return re.Min == 0 || matchesWithoutSlash(re.Sub[0])
+	case syntax.OpPlus, syntax.OpCapture:
+		return matchesWithoutSlash(re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !matchesWithoutSlash(sub) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if matchesWithoutSlash(sub) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+type templateVar struct {
+	name       string
+	pattern    string
+	start, end int
+}
+
+// templateVars finds all params in the template,
+// like {name} or {name:pattern}
+func templateVars(tpl string) ([]templateVar, error) {
+	var vars []templateVar
+	level, start := 0, 0
+
+	for i := 0; i < len(tpl); i++ {
+		switch tpl[i] {
+		case '{':
+			if level == 0 {
+				start = i
+			}
+			level++
+		case '}':
+			level--
+			if level < 0 {
+				return nil, errors.Errorf("unbalanced braces in '%s'", tpl)
+			}
+			if level > 0 {
+				continue
+			}
+
+			v := templateVar{name: tpl[start+1 : i], start: start, end: i + 1}
+			if j := strings.Index(v.name, ":"); j != -1 {
+				v.name, v.pattern = v.name[:j], v.name[j+1:]
+			}
+			if v.name == "" {
+				return nil, errors.Errorf("missing param name in '%s'", tpl)
+			}
+
+			if v.pattern != "" {
+				re, err := regexp.Compile(v.pattern)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid pattern for param '%s'", v.name)
+				}
+				if re.NumSubexp() > 0 {
+					return nil, errors.Errorf("pattern for param '%s' cannot contain capturing groups", v.name)
+				}
+			}
+
+			vars = append(vars, v)
+		}
+	}
+
+	if level != 0 {
+		return nil, errors.Errorf("unbalanced braces in '%s'", tpl)
+	}
+
+	return vars, nil
+}
+
+// compileTemplate compiles the template to a regexp where every param is a group
+func compileTemplate(tpl string, vars []templateVar, defaultPattern string) (*regexp.Regexp, []string) {
+	var b bytes.Buffer
+	names := make([]string, 0, len(vars))
+	end := 0
+
+	b.WriteString("^")
+	for _, v := range vars {
+		b.WriteString(regexp.QuoteMeta(tpl[end:v.start]))
+		pattern := v.pattern
+		if pattern == "" {
+			pattern = defaultPattern
+		}
+		b.WriteString("(" + pattern + ")")
+		names = append(names, v.name)
+		end = v.end
+	}
+	b.WriteString(regexp.QuoteMeta(tpl[end:]))
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String()), names
+}
+
+// compileHost compiles a host template like "{tenant}.example.com",
+// hosts are matched without regard to case
+func compileHost(tpl string) (*regexp.Regexp, []string, error) {
+	vars, err := templateVars(tpl)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid host '%s'", tpl)
+	}
+	re, names := compileTemplate(tpl, vars, "[^.]+")
+	return regexp.MustCompile("(?i)" + re.String()), names, nil
+}
+
+// node is a node in the tree of path segments. Static children are
+// matched before params and params are matched before the catch-all
+type node struct {
+	seg      segment
+	static   map[string]*node
+	params   []*node
+	catchAll *node
+	routes   map[string][]*Route
+	handler  func(*context)
+}
+
+func (n *node) insert(segs []segment) *node {
+	for _, seg := range segs {
+		n = n.child(seg)
+	}
+	return n
+}
+
+// child returns the child of the node for the segment, the child is created
+// if it does not exist. An existing child is replaced with a copy, so n must
+// be a copy that is not used by any table yet
+func (n *node) child(seg segment) *node {
+	switch seg.kind {
+	case staticSegment:
+		if n.static == nil {
+			n.static = map[string]*node{}
+		}
+		c, ok := n.static[seg.tpl]
+		if ok {
+			c = c.clone()
+		} else {
+			c = &node{seg: seg}
+		}
+		n.static[seg.tpl] = c
+		return c
+	case catchAllSegment:
+		c := &node{seg: seg}
+		if n.catchAll != nil {
+			c = n.catchAll.clone()
+		}
+		// a prefix handler has a catch-all without a name
+		if c.seg.name == "" {
+			c.seg = seg
+		}
+		n.catchAll = c
+		return c
+	}
+
+	for i, c := range n.params {
+		if c.seg.tpl == seg.tpl {
+			c = c.clone()
+			n.params[i] = c
+			return c
+		}
+	}
+
+	// params with a pattern are tried before params without
+	c := &node{seg: seg}
+	i := len(n.params)
+	if seg.re != nil {
+		for i = 0; i < len(n.params) && n.params[i].seg.re != nil; i++ {
+		}
+	}
+	n.params = append(n.params, nil)
+	copy(n.params[i+1:], n.params[i:])
+	n.params[i] = c
+
+	return c
+}
+
+// clone returns a copy of the node that can be changed
+// without changing the tables that use the node
+func (n *node) clone() *node {
+	c := *n
+	if n.static != nil {
+		c.static = make(map[string]*node, len(n.static))
+		for k, v := range n.static {
+			c.static[k] = v
+		}
+	}
+	c.params = append([]*node(nil), n.params...)
+	if n.routes != nil {
+		c.routes = make(map[string][]*Route, len(n.routes))
+		for k, v := range n.routes {
+			c.routes[k] = v
+		}
+	}
+	return &c
+}
+
+// find returns the node for the exact segments, or nil if there is none
+func (n *node) find(segs []segment) *node {
+	for _, seg := range segs {
+		var c *node
+		switch seg.kind {
+		case staticSegment:
+			c = n.static[seg.tpl]
+		case catchAllSegment:
+			c = n.catchAll
+		default:
+			for _, pc := range n.params {
+				if pc.seg.tpl == seg.tpl {
+					c = pc
+					break
+				}
+			}
+		}
+		if c == nil {
+			return nil
+		}
+		n = c
+	}
+	return n
+}
+
+// overlaps calls fn with every node that matches the same paths as the segments,
+// static segments are only overlapped by themselves and params by params
+// with the same pattern
+func (n *node) overlaps(segs []segment, fn func(*node)) {
+	if len(segs) == 0 {
+		fn(n)
+		return
+	}
+
+	seg, rest := segs[0], segs[1:]
+	switch seg.kind {
+	case staticSegment:
+		if c, ok := n.static[seg.tpl]; ok {
+			c.overlaps(rest, fn)
+		}
+	case catchAllSegment:
+		if n.catchAll != nil {
+			fn(n.catchAll)
+		}
+	default:
+		for _, c := range n.params {
+			if c.seg.same(seg) {
+				c.overlaps(rest, fn)
+			}
+		}
+	}
+}
+
+// same checks if the segments matches the same values,
+// the names of the params are not compared
+func (s segment) same(o segment) bool {
+	if s.kind != o.kind {
+		return false
+	}
+	if s.re == nil || o.re == nil {
+		return s.re == o.re
+	}
+	return s.re.String() == o.re.String()
+}
+
+// add adds the route to the node. Routes with Matchers are tried before the
+// route without, in the order they were added. A route with the same method
+// and Matchers as a route that already is added is ignored, and the route
+// that already is added is returned
+func (n *node) add(route *Route) *Route {
+	if n.routes == nil {
+		n.routes = map[string][]*Route{}
+	}
+
+	routes := n.routes[route.Method]
+	key := route.matcherKey()
+	for _, r := range routes {
+		if r.matcherKey() == key {
+			return r
+		}
+	}
+
+	i := len(routes)
+	if len(route.matchers) > 0 {
+		for i = 0; i < len(routes) && len(routes[i].matchers) > 0; i++ {
+		}
+	}
+
+	// the slice can be used by other tables so a new one is created
+	added := make([]*Route, 0, len(routes)+1)
+	added = append(added, routes[:i]...)
+	added = append(added, route)
+	n.routes[route.Method] = append(added, routes[i:]...)
+	return nil
+}
+
+// route returns the first route of the method that matches the request
+func (n *node) route(method string, req *http.Request) *Route {
+	for _, route := range n.routes[method] {
+		if route.matches(req) {
+			return route
+		}
+	}
+	return nil
+}
+
+// has checks if the node has routes for the method,
+// HEAD is included if the node has GET routes
+func (n *node) has(method string) bool {
+	if len(n.routes[method]) > 0 {
+		return true
+	}
+	return method == "HEAD" && len(n.routes["GET"]) > 0
+}
+
+// accepts checks if the node can serve the request,
+// HEAD is accepted if the node has a GET route
+func (n *node) accepts(req *http.Request) bool {
+	if n.handler != nil || n.route(req.Method, req) != nil {
+		return true
+	}
+	return req.Method == "HEAD" && n.route("GET", req) != nil
+}
+
+// serve serves the request with the route of the method,
+// HEAD requests are served by the GET route with the body discarded
+func (n *node) serve(c *context) {
+	method := c.req.Method
+	if route := n.route(method, c.req); route != nil {
+		route.serve(c)
+		return
+	}
+
+	if route := n.route("GET", c.req); route != nil && method == "HEAD" {
+		hw := &headResponseWriter{ResponseWriter: c.res.ResponseWriter}
+		c.res.ResponseWriter = hw
+		route.serve(c)
+		hw.flush()
+		return
+	}
+
+	n.handler(c)
+}
+
+func (n *node) leaf() bool {
+	return n.handler != nil || len(n.routes) > 0
+}
+
+// search finds the node that matches the rest of the path and accepts the request.
+// The first node that matches the path but has no route for the method is stored
+// in first, which is used to tell a 404 from a 405
+func (n *node) search(p string, req *http.Request, ps *Params, first **node) *node {
+	seg, rest, last := splitSegment(p)
+
+	if c, ok := n.static[seg]; ok {
+		if found := c.descend(rest, last, req, ps, first); found != nil {
+			return found
+		}
+	}
+
+	for _, c := range n.params {
+		l := len(*ps)
+		if !c.capture(seg, ps) {
+			continue
+		}
+		if found := c.descend(rest, last, req, ps, first); found != nil {
+			return found
+		}
+		*ps = (*ps)[:l]
+	}
+
+	if c := n.catchAll; c != nil {
+		if c.accepts(req) {
+			if c.seg.name != "" {
+				*ps = append(*ps, Param{Key: c.seg.name, Value: p})
+			}
+			return c
+		}
+		if *first == nil && c.leaf() && !c.has(req.Method) {
+			*first = c
+		}
+	}
+
+	return nil
+}
+
+func (n *node) descend(rest string, last bool, req *http.Request, ps *Params, first **node) *node {
+	if !last {
+		return n.search(rest, req, ps, first)
+	}
+
+	if n.accepts(req) {
+		return n
+	}
+	if *first == nil && n.leaf() && !n.has(req.Method) {
+		*first = n
+	}
+
+	return nil
+}
+
+// collect adds the methods of all nodes that matches the rest of the path
+func (n *node) collect(p string, methods map[string]bool) {
+	seg, rest, last := splitSegment(p)
+
+	collect := func(c *node) {
+		if !last {
+			c.collect(rest, methods)
+			return
+		}
+		for m := range c.routes {
+			methods[m] = true
+		}
+	}
+
+	if c, ok := n.static[seg]; ok {
+		collect(c)
+	}
+
+	var ps Params
+	for _, c := range n.params {
+		if c.capture(seg, &ps) {
+			collect(c)
+		}
+	}
+
+	if n.catchAll != nil {
+		for m := range n.catchAll.routes {
+			methods[m] = true
+		}
+	}
+}
+
+// capture checks if the segment matches the param
+// node and adds the values of the params to ps
+func (n *node) capture(seg string, ps *Params) bool {
+	if seg == "" {
+		return false
+	}
+
+	switch n.seg.kind {
+	case paramSegment:
+		if n.seg.re != nil && !n.seg.re.MatchString(seg) {
+			return false
+		}
+		*ps = append(*ps, Param{Key: n.seg.name, Value: seg})
+		return true
+	case patternSegment:
+		m := n.seg.re.FindStringSubmatch(seg)
+		if m == nil {
+			return false
+		}
+		for i, name := range n.seg.names {
+			*ps = append(*ps, Param{Key: name, Value: m[i+1]})
+		}
+		return true
+	}
+
+	return false
+}
+
+func splitSegment(p string) (seg, rest string, last bool) {
+	if i := strings.IndexByte(p, '/'); i != -1 {
+		return p[:i], p[i+1:], false
+	}
+	return p, "", true
+}
+
+// hostTree holds the routes for a host
+type hostTree struct {
+	tpl   string
+	re    *regexp.Regexp
+	names []string
+	root  *node
+}
+
+// tree holds all routes of a Router and its groups. The routes are kept
+// in a table that is replaced on every change, so requests can use a table
+// without locking while routes are added or removed
+type tree struct {
+	mu    sync.Mutex
+	table atomic.Value
+}
+
+// table is a snapshot of the routes in a tree
+type table struct {
+	root  *node
+	hosts []*hostTree
+}
+
+func newTree() *tree {
+	t := &tree{}
+	t.table.Store(&table{root: &node{}})
+	return t
+}
+
+// load returns the current table
+func (t *tree) load() *table {
+	return t.table.Load().(*table)
+}
+
+// update calls fn with a copy of the current table, the copy replaces the
+// current table if fn does not return an error. Requests that already
+// loaded the current table keep using it
+func (t *tree) update(fn func(*table) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	old := t.load()
+	tb := &table{root: old.root.clone(), hosts: make([]*hostTree, len(old.hosts))}
+	for i, h := range old.hosts {
+		hc := *h
+		hc.root = h.root.clone()
+		tb.hosts[i] = &hc
+	}
+
+	if err := fn(tb); err != nil {
+		return err
+	}
+
+	t.table.Store(tb)
+	return nil
+}
+
+// insert adds the path to the tree of the host
+// and returns the node at the end of the path
+func (t *table) insert(host, p string) (*node, error) {
+	segs, err := parseTemplate(p)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := t.hostRoot(host)
+	if err != nil {
+		return nil, err
+	}
+
+	return root.insert(segs), nil
+}
+
+// conflict finds a route with the method and Matchers of the route that
+// matches the same paths as the template. A catch-all can only have one name,
+// so a route with another name for it conflicts with the routes of all methods
+func (t *table) conflict(host, p string, route *Route) *Route {
+	segs, err := parseTemplate(p)
+	if err != nil {
+		return nil
+	}
+
+	root, err := t.hostRoot(host)
+	if err != nil {
+		return nil
+	}
+
+	var found *Route
+	root.overlaps(segs, func(n *node) {
+		if found != nil {
+			return
+		}
+		for _, r := range n.routes[route.Method] {
+			if r.matcherKey() == route.matcherKey() {
+				found = r
+				return
+			}
+		}
+		if last := segs[len(segs)-1]; last.kind == catchAllSegment && n.seg.name != last.name {
+			for _, routes := range n.routes {
+				if found == nil || routes[0].Method < found.Method {
+					found = routes[0]
+				}
+			}
+		}
+	})
+
+	return found
+}
+
+// remove removes the route with the method, the Matchers of the key
+// and the exact path from the tree of the host and returns it
+func (t *table) remove(host, p, method, key string) *Route {
+	segs, err := parseTemplate(p)
+	if err != nil {
+		return nil
+	}
+
+	root := t.root
+	if host != "" {
+		root = nil
+		for _, h := range t.hosts {
+			if h.tpl == host {
+				root = h.root
+			}
+		}
+	}
+
+	if root == nil {
+		return nil
+	}
+	n := root.find(segs)
+	if n == nil {
+		return nil
+	}
+
+	for i, route := range n.routes[method] {
+		if route.matcherKey() != key {
+			continue
+		}
+
+		n = root.insert(segs)
+		routes := n.routes[method]
+		if len(routes) == 1 {
+			delete(n.routes, method)
+		} else {
+			n.routes[method] = append(routes[:i:i], routes[i+1:]...)
+		}
+		return route
+	}
+
+	return nil
+}
+
+func (t *table) hostRoot(host string) (*node, error) {
+	if host == "" {
+		return t.root, nil
+	}
+
+	for _, h := range t.hosts {
+		if h.tpl == host {
+			return h.root, nil
+		}
+	}
+
+	re, names, err := compileHost(host)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &hostTree{tpl: host, re: re, names: names, root: &node{}}
+	t.hosts = append(t.hosts, h)
+
+	return h.root, nil
+}
+
+// lookup finds the node that matches the host and path and accepts the request.
+// Routes with a host are matched before routes without. If no node accepts the
+// request the first node that matched the path is returned as first
+func (t *table) lookup(host, p string, req *http.Request, ps *Params) (n, first *node) {
+	for _, h := range t.hosts {
+		m := h.re.FindStringSubmatch(host)
+		if m == nil {
+			continue
+		}
+
+		l := len(*ps)
+		for i, name := range h.names {
+			*ps = append(*ps, Param{Key: name, Value: m[i+1]})
+		}
+		if n = h.root.search(p[1:], req, ps, &first); n != nil {
+			return n, first
+		}
+		*ps = (*ps)[:l]
+	}
+
+	return t.root.search(p[1:], req, ps, &first), first
+}
+
+// methods returns all methods that have a route matching the host and path
+func (t *table) methods(host, p string) map[string]bool {
+	methods := map[string]bool{}
+	for _, h := range t.hosts {
+		if h.re.MatchString(host) {
+			h.root.collect(p[1:], methods)
+		}
+	}
+	t.root.collect(p[1:], methods)
+	return methods
+}
+
+// buildPath builds a path from the template with the params
+// which are provided as key value pairs
+func buildPath(tpl string, pairs []string) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", errors.Errorf("params must be key value pairs, got %d values", len(pairs))
+	}
+
+	values := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		values[pairs[i]] = pairs[i+1]
+	}
+
+	// the value of a catch-all can contain slashes
+	// so every part of it is escaped by itself
+	catchAll := ""
+	if prefix, name := splitCatchAll(tpl); name != "" {
+		value, ok := values[name]
+		if !ok {
+			return "", errors.Errorf("missing route variable %q", name)
+		}
+
+		parts := strings.Split(strings.TrimPrefix(value, "/"), "/")
+		for j, part := range parts {
+			parts[j] = url.PathEscape(part)
+		}
+
+		tpl, catchAll = prefix, strings.Join(parts, "/")
+	}
+
+	vars, err := templateVars(tpl)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	end := 0
+	for _, v := range vars {
+		b.WriteString(tpl[end:v.start])
+		end = v.end
+
+		value, ok := values[v.name]
+		if !ok {
+			return "", errors.Errorf("missing route variable %q", v.name)
+		}
+
+		pattern := v.pattern
+		if pattern == "" {
+			pattern = "[^/]+"
+		}
+		if !regexp.MustCompile("^(?:" + pattern + ")$").MatchString(value) {
+			return "", errors.Errorf("variable %q doesn't match, expected %q", value, pattern)
+		}
+
+		b.WriteString(url.PathEscape(value))
+	}
+	b.WriteString(tpl[end:])
+	b.WriteString(catchAll)
+
+	return b.String(), nil
+}
+
+// splitCatchAll splits the template into the part before
+// the catch-all and the name of the catch-all, if it has one
+func splitCatchAll(tpl string) (prefix, name string) {
+	i := strings.LastIndexByte(tpl, '/')
+	if !strings.HasPrefix(tpl[i+1:], "*") {
+		return tpl, ""
+	}
+	return tpl[:i+1], tpl[i+2:]
+}
+
+// hostname returns the host of the request in lower case without the port
+func hostname(req *http.Request) string {
+	host := req.Host
+	if req.URL.IsAbs() {
+		host = req.URL.Host
+	}
+	if i := strings.LastIndexByte(host, ':'); i > strings.LastIndexByte(host, ']') {
+		host = host[:i]
+	}
+	return strings.ToLower(host)
+}
+
+// cleanPath returns the canonical path for p, eliminating . and .. elements
+// and keeping the trailing slash
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+	np := path.Clean(p)
+	if p[len(p)-1] == '/' && np != "/" {
+		np += "/"
+	}
+	return np
+}
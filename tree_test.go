@@ -0,0 +1,188 @@
+package otto
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Precedence(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	handler := func(name string) HandlerFunc {
+		return func(ctx Context) error {
+			return ctx.String(200, fmt.Sprintf("%s %v", name, ctx.Params()))
+		}
+	}
+
+	r.GET("/users/{id}", handler("param"))
+	r.GET("/users/{id:int}", handler("int"))
+	r.GET("/users/me", handler("static"))
+	r.GET("/users/{id}/posts", handler("posts"))
+	r.GET("/users/me/settings", handler("settings"))
+	r.GET("/files/{name}.{ext}", handler("pattern"))
+	r.GET("/v{version:[0-9]+}/status", handler("version"))
+	r.GET("/docs/{name:[^.]+}.{ext}", handler("docs"))
+	r.GET("/tags/{tag:\\S+}", handler("tag"))
+	r.GET("/pages/{path:.+}", handler("page"))
+
+	table := []struct {
+		path string
+		body string
+	}{
+		{"/users/me", "static []"},
+		{"/users/1", "int [{id 1}]"},
+		{"/users/abc", "param [{id abc}]"},
+		{"/users/me/posts", "posts [{id me}]"},
+		{"/users/me/settings", "settings []"},
+		{"/files/report.pdf", "pattern [{name report} {ext pdf}]"},
+		{"/v2/status", "version [{version 2}]"},
+		{"/docs/report.pdf", "docs [{name report} {ext pdf}]"},
+		{"/tags/go-1.10", "tag [{tag go-1.10}]"},
+		{"/pages/about", "page [{path about}]"},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest("GET", tc.path, nil))
+		assert.Equal(t, 200, res.Code, tc.path)
+		assert.Equal(t, tc.body, res.Body.String(), tc.path)
+	}
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/users/", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	// a param pattern only matches a single segment
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/pages/a/b", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
+
+func Test_Tree_Catch_All(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	handler := func(name string) HandlerFunc {
+		return func(ctx Context) error {
+			return ctx.String(200, fmt.Sprintf("%s %v", name, ctx.Params()))
+		}
+	}
+
+	r.GET("/files/*path", handler("files"))
+	r.GET("/files/{name}", handler("param"))
+	r.GET("/files/readme", handler("static"))
+	r.GET("/users/{id}/*rest", handler("rest"))
+
+	table := []struct {
+		path string
+		body string
+	}{
+		{"/files/readme", "static []"},
+		{"/files/a.txt", "param [{name a.txt}]"},
+		{"/files/a/b/c.txt", "files [{path a/b/c.txt}]"},
+		{"/files/readme/more", "files [{path readme/more}]"},
+		{"/files/", "files [{path }]"},
+		{"/users/1/a/b", "rest [{id 1} {rest a/b}]"},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest("GET", tc.path, nil))
+		assert.Equal(t, 200, res.Code, tc.path)
+		assert.Equal(t, tc.body, res.Body.String(), tc.path)
+	}
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/files", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("POST", "/files/a/b", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+}
+
+func Test_Tree_Clean_Path(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/a/b", func(ctx Context) error {
+		return ctx.NoContent()
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/a/../a//b?q=1", nil))
+	assert.Equal(t, http.StatusMovedPermanently, res.Code)
+	assert.Equal(t, "/a/b?q=1", res.Header().Get(HeaderLocation))
+}
+
+func Test_Tree_Strict_Slash(t *testing.T) {
+	t.Parallel()
+
+	for _, strict := range []bool{true, false} {
+		r := NewRouter(strict)
+
+		r.GET("/asd", func(ctx Context) error {
+			return ctx.NoContent()
+		})
+
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest("GET", "/asd/", nil))
+
+		if strict {
+			assert.Equal(t, http.StatusMovedPermanently, res.Code)
+			assert.Equal(t, "/asd", res.Header().Get(HeaderLocation))
+		} else {
+			assert.Equal(t, http.StatusNotFound, res.Code)
+		}
+	}
+}
+
+func Test_Tree_Invalid_Template(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	h := func(ctx Context) error {
+		return nil
+	}
+
+	assert.Panics(t, func() { r.GET("/users/{id", h) })
+	assert.Panics(t, func() { r.GET("/users/{id}/{id}", h) })
+	assert.Panics(t, func() { r.GET("/users/{id:(a|b)}", h) })
+	assert.Panics(t, func() { r.GET("/users/{id:[0-9}", h) })
+	assert.Panics(t, func() { r.GET("/files/*", h) })
+	assert.Panics(t, func() { r.GET("/files/*path/edit", h) })
+	assert.Panics(t, func() { r.GET("/files/{path}/*path", h) })
+	assert.Panics(t, func() { r.GET("/files/{path:a/b}", h) })
+	assert.Panics(t, func() { r.GET("/files/{path:(?:a|b)+/}", h) })
+	assert.NotPanics(t, func() { r.GET("/users/{id:[0-9]{2}}", h) })
+	assert.NotPanics(t, func() { r.GET("/users/{name:[^/]+}/{slug:slug}", h) })
+	assert.NotPanics(t, func() { r.GET("/files/{path:.+}", h) })
+	assert.NotPanics(t, func() { r.GET("/files/{name:[^.]+}.{ext}", h) })
+	assert.NotPanics(t, func() { r.GET("/users/{id:\\S+}/edit", h) })
+}
+
+func Test_Build_Path(t *testing.T) {
+	t.Parallel()
+
+	p, err := buildPath("/users/{id:[0-9]+}/{name}", []string{"id", "1", "name", "otto bot"})
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, "/users/1/otto%20bot", p)
+
+	_, err = buildPath("/users/{id}", []string{"id"})
+	assert.Error(t, err, "should throw error")
+
+	_, err = buildPath("/users/{id}", []string{"id", "a/b"})
+	assert.Error(t, err, "should throw error")
+
+	p, err = buildPath("/users/{id}/files/*path", []string{"id", "1", "path", "a b/c.txt"})
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, "/users/1/files/a%20b/c.txt", p)
+
+	_, err = buildPath("/files/*path", []string{})
+	assert.Error(t, err, "should throw error")
+}
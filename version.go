@@ -0,0 +1,150 @@
+package otto
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Versioning decides how the API version of a request is resolved
+// for the groups created with Router.Version
+type Versioning struct {
+	// Prefix adds the version to the path after the prefix of the group, like
+	// /api/v2/users. Requests without a version in the path use the Default
+	// version
+	Prefix bool
+	// Header is the name of a header with the version, like Accept-Version
+	Header string
+	// Vendor is the vendor of media types in the Accept header with the
+	// version, like "otto" for application/vnd.otto.v2+json
+	Vendor string
+	// Default is the version of requests without a version
+	Default string
+}
+
+// SetVersioning sets how the version of requests is resolved, it
+// is used by the groups that are created with Version afterwards
+func (r *Router) SetVersioning(v Versioning) {
+	r.versioning = v
+}
+
+// Version creates a group for the API version. Depending on the Versioning
+// of the Router the routes are prefixed with the version or only match
+// requests that resolves to the version. The version is available
+// with Context.Version
+func (r *Router) Version(version string, fn ...func(*Router)) *Router {
+	var g *Router
+	if r.versioning.Prefix {
+		g = r.group(path.Join(r.prefix, version), r.host, nil)
+
+		if r.versioning.Default != "" {
+			root := r.root()
+			r.mu.Lock()
+			if root.versions == nil {
+				root.versions = map[string]string{}
+			}
+			root.versions[r.prefix] = r.versioning.Default
+			r.mu.Unlock()
+		}
+	} else {
+		g = r.group(r.prefix, r.host, nil)
+		g.matchers = append(g.matchers[:len(g.matchers):len(g.matchers)], versionMatcher{
+			versioning: r.versioning,
+			version:    version,
+		})
+	}
+	g.version = version
+
+	for _, f := range fn {
+		f(g)
+	}
+
+	return g
+}
+
+// defaultVersionPaths returns the path with the default version after the
+// prefix of the groups where versions are created, longest prefix first
+func (r *Router) defaultVersionPaths(p string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var prefixes []string
+	for prefix := range r.versions {
+		if prefix == "/" || p == prefix || strings.HasPrefix(p, prefix+"/") {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+
+	paths := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		prefix := strings.TrimSuffix(prefix, "/")
+		rest := strings.TrimPrefix(p, prefix)
+		if rest == "/" {
+			rest = ""
+		}
+		paths[i] = prefix + "/" + r.versions[prefixes[i]] + rest
+	}
+
+	return paths
+}
+
+// resolve returns the version of the request from the header or the
+// media types of the Accept header, or the default version
+func (v Versioning) resolve(req *http.Request) string {
+	if v.Header != "" {
+		if version := req.Header.Get(v.Header); version != "" {
+			return version
+		}
+	}
+
+	if v.Vendor != "" {
+		prefix := "application/vnd." + strings.ToLower(v.Vendor) + "."
+		for _, accept := range strings.Split(req.Header.Get(HeaderAccept), ",") {
+			mt, _, err := mime.ParseMediaType(accept)
+			if err != nil || !strings.HasPrefix(mt, prefix) {
+				continue
+			}
+			version := mt[len(prefix):]
+			if i := strings.IndexByte(version, '+'); i != -1 {
+				version = version[:i]
+			}
+			return version
+		}
+	}
+
+	return v.Default
+}
+
+// vary adds the headers that the version is resolved from to the Vary header
+func (v Versioning) vary(h http.Header) {
+	if v.Header != "" {
+		h.Add(HeaderVary, v.Header)
+	}
+	if v.Vendor != "" {
+		h.Add(HeaderVary, HeaderAccept)
+	}
+}
+
+// sameVersion compares versions where the "v" is optional, like "v2" and "2"
+func sameVersion(a, b string) bool {
+	return a != "" && strings.TrimPrefix(strings.ToLower(a), "v") == strings.TrimPrefix(strings.ToLower(b), "v")
+}
+
+type versionMatcher struct {
+	versioning Versioning
+	version    string
+}
+
+func (m versionMatcher) Match(req *http.Request) bool {
+	return sameVersion(m.versioning.resolve(req), m.version)
+}
+
+func (m versionMatcher) String() string {
+	return "version " + m.version
+}
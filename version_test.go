@@ -0,0 +1,148 @@
+package otto
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func versionHandler(ctx Context) error {
+	return ctx.String(200, ctx.Version())
+}
+
+func Test_Router_Version_Header(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+	r.SetVersioning(Versioning{
+		Header:  "Accept-Version",
+		Vendor:  "otto",
+		Default: "v1",
+	})
+
+	api := r.Group("/api")
+	api.Version("v1", func(v1 *Router) {
+		v1.GET("/users", versionHandler)
+	})
+	api.Version("v2").GET("/users", versionHandler)
+	api.GET("/status", versionHandler)
+
+	table := []struct {
+		path   string
+		header string
+		value  string
+		code   int
+		body   string
+	}{
+		{"/api/users", "", "", 200, "v1"},
+		{"/api/users", "Accept-Version", "v2", 200, "v2"},
+		{"/api/users", "Accept-Version", "2", 200, "v2"},
+		{"/api/users", "Accept", "application/vnd.otto.v2+json", 200, "v2"},
+		{"/api/users", "Accept", "text/html, application/vnd.otto.v1+json;q=0.9", 200, "v1"},
+		{"/api/users", "Accept-Version", "v3", 404, ""},
+		{"/api/status", "Accept-Version", "v3", 200, "v3"},
+		{"/api/status", "", "", 200, "v1"},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", tc.path, nil)
+		if tc.header != "" {
+			req.Header.Set(tc.header, tc.value)
+		}
+		r.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.code, res.Code, tc.path+" "+tc.value)
+		if tc.code == 200 {
+			assert.Equal(t, tc.body, res.Body.String(), tc.path+" "+tc.value)
+		}
+	}
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/api/users", nil))
+	assert.Equal(t, []string{"Accept-Version", "Accept"}, res.Header()[HeaderVary])
+}
+
+func Test_Router_Version_Prefix(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+	r.SetVersioning(Versioning{
+		Prefix:  true,
+		Default: "v1",
+	})
+
+	r.Version("v1").GET("/users", versionHandler)
+	r.Version("v2", func(v2 *Router) {
+		v2.GET("/users", versionHandler)
+		v2.GET("/", versionHandler)
+	})
+
+	table := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/v1/users", 200, "v1"},
+		{"/v2/users", 200, "v2"},
+		{"/users", 200, "v1"},
+		{"/v2", 200, "v2"},
+		{"/v3/users", 404, ""},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest("GET", tc.path, nil))
+
+		assert.Equal(t, tc.code, res.Code, tc.path)
+		if tc.code == 200 {
+			assert.Equal(t, tc.body, res.Body.String(), tc.path)
+		}
+		assert.Empty(t, res.Header().Get(HeaderVary), tc.path)
+	}
+
+	routes := r.Routes()
+	assert.Equal(t, "/v1/users", routes[0].Path)
+}
+
+func Test_Router_Version_Prefix_Group(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+	r.SetVersioning(Versioning{
+		Prefix:  true,
+		Default: "v1",
+	})
+
+	api := r.Group("/api")
+	api.Version("v1", func(v1 *Router) {
+		v1.GET("/users", versionHandler)
+		v1.GET("/", versionHandler)
+	})
+	api.Version("v2").GET("/users", versionHandler)
+	r.GET("/users", func(ctx Context) error {
+		return ctx.String(200, "root")
+	})
+
+	table := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/api/v1/users", 200, "v1"},
+		{"/api/v2/users", 200, "v2"},
+		{"/api/users", 200, "v1"},
+		{"/api", 200, "v1"},
+		{"/users", 200, "root"},
+		{"/apiusers", 404, ""},
+		{"/v1/users", 404, ""},
+	}
+
+	for _, tc := range table {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest("GET", tc.path, nil))
+
+		assert.Equal(t, tc.code, res.Code, tc.path)
+		if tc.code == 200 {
+			assert.Equal(t, tc.body, res.Body.String(), tc.path)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package otto
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// WrapHandler wraps a http.Handler to a HandlerFunc
+func WrapHandler(h http.Handler) HandlerFunc {
+	return func(ctx Context) error {
+		h.ServeHTTP(ctx.Response(), ctx.Request())
+		return nil
+	}
+}
+
+// WrapHandlerFunc wraps a http.HandlerFunc to a HandlerFunc
+func WrapHandlerFunc(h http.HandlerFunc) HandlerFunc {
+	return WrapHandler(h)
+}
+
+// WrapMiddleware wraps a net/http middleware to a Middleware.
+// The request and response writer that the net/http middleware
+// passes on will be used by the Context in the next HandlerFunc.
+// An error of the next HandlerFunc is rendered before the net/http
+// middleware returns, so that it sees the error response
+func WrapMiddleware(m func(http.Handler) http.Handler) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			c, ok := ctx.(*context)
+			if !ok {
+				return errors.Errorf("WrapMiddleware does not support Context of type %T", ctx)
+			}
+
+			var err error
+			outer := c.res
+
+			h := m(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				c.req = req
+				if res != outer {
+					// the response writer wraps the Response, so a new
+					// Response is used by the next HandlerFunc
+					c.res = &Response{ResponseWriter: res}
+					defer func() {
+						c.res = outer
+					}()
+				}
+
+				if err = next(c); err != nil && c.router != nil {
+					c.router.renderError(err, c)
+					err = nil
+				}
+			}))
+
+			// if the net/http middleware does not call the next handler
+			// it has already written the response
+			h.ServeHTTP(outer, c.req)
+
+			return err
+		}
+	}
+}
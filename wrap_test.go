@@ -0,0 +1,226 @@
+package otto
+
+import (
+	gocontext "context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey string
+
+func Test_WrapHandler(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.GET("/asd", WrapHandler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(201)
+		res.Write([]byte("wrapped"))
+	})))
+
+	r.GET("/func", WrapHandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("func"))
+	}))
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(fmt.Sprintf("%s/asd", ts.URL))
+	assert.NoError(t, err, "should not throw any error")
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, 201, res.StatusCode)
+	assert.Equal(t, "wrapped", string(b))
+
+	res, err = http.Get(fmt.Sprintf("%s/func", ts.URL))
+	assert.NoError(t, err, "should not throw any error")
+	b, _ = ioutil.ReadAll(res.Body)
+	assert.Equal(t, "func", string(b))
+}
+
+func Test_WrapMiddleware(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.Use(WrapMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if req.Header.Get(HeaderAuthorization) == "" {
+				http.Error(res, "unauthorized", 401)
+				return
+			}
+			res.Header().Set("X-Wrapped", "true")
+			req = req.WithContext(gocontext.WithValue(req.Context(), ctxKey("user"), "otto"))
+			next.ServeHTTP(res, req)
+		})
+	}))
+
+	r.GET("/asd", func(ctx Context) error {
+		ctx.Set("key", "value")
+		user := ctx.Request().Context().Value(ctxKey("user")).(string)
+		return ctx.String(200, user)
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/asd", ts.URL), nil)
+	assert.NoError(t, err, "should not throw any error")
+	req.Header.Set(HeaderAuthorization, "token")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err, "should not throw any error")
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "otto", string(b))
+	assert.Equal(t, "true", res.Header.Get("X-Wrapped"))
+
+	res, err = http.Get(fmt.Sprintf("%s/asd", ts.URL))
+	assert.NoError(t, err, "should not throw any error")
+	assert.Equal(t, 401, res.StatusCode)
+}
+
+type upperWriter struct {
+	http.ResponseWriter
+}
+
+func (w upperWriter) Write(b []byte) (int, error) {
+	for i, c := range b {
+		if c >= 'a' && c <= 'z' {
+			b[i] = c - 32
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func Test_WrapMiddleware_Response_Writer(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.Use(WrapMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(upperWriter{res}, req)
+		})
+	}))
+
+	r.GET("/asd", func(ctx Context) error {
+		return ctx.String(200, "asd")
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(fmt.Sprintf("%s/asd", ts.URL))
+	assert.NoError(t, err, "should not throw any error")
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, "ASD", string(b))
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	code *int
+}
+
+func (w statusWriter) WriteHeader(code int) {
+	*w.code = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func Test_WrapMiddleware_Error(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	var code int
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			err := next(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, 418, ctx.Response().code)
+			assert.NotZero(t, ctx.Response().Size())
+			return err
+		}
+	})
+
+	r.Use(WrapMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			_, ok := res.(*Response)
+			assert.True(t, ok, "should get the Response of the Context")
+			next.ServeHTTP(statusWriter{res, &code}, req)
+		})
+	}))
+
+	r.GET("/asd", func(ctx Context) error {
+		return ctx.Error(418, fmt.Errorf("teapot"))
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/asd", nil))
+	assert.Equal(t, 418, res.Code)
+	assert.Equal(t, 418, code)
+	assert.Contains(t, res.Body.String(), "teapot")
+}
+
+func Test_WrapMiddleware_Timeout(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	r.Use(WrapMiddleware(func(next http.Handler) http.Handler {
+		return http.TimeoutHandler(next, 10*time.Millisecond, "timeout")
+	}))
+
+	wait := make(chan struct{})
+	done := make(chan string)
+	r.GET("/slow/{id}", func(ctx Context) error {
+		<-wait
+		// the handler keeps running after the request has timed out
+		done <- ctx.Params().String("id")
+		return ctx.String(200, "slow")
+	})
+
+	r.GET("/fast/{id}", func(ctx Context) error {
+		return ctx.String(200, ctx.Params().String("id"))
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/slow/1", nil))
+	assert.Equal(t, 503, res.Code)
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/fast/2", nil))
+	assert.Equal(t, "2", res.Body.String())
+
+	close(wait)
+	assert.Equal(t, "1", <-done)
+}
+
+func Test_Router_Mount(t *testing.T) {
+	t.Parallel()
+	r := NewRouter(false)
+
+	triggered := false
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			triggered = true
+			return next(ctx)
+		}
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vars", func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(req.URL.Path))
+	})
+
+	r.Group("/debug").Mount("/std", mux)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(fmt.Sprintf("%s/debug/std/vars", ts.URL))
+	assert.NoError(t, err, "should not throw any error")
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "/vars", string(b))
+	assert.True(t, triggered, "middleware should be triggered")
+}